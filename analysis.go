@@ -0,0 +1,142 @@
+package channeler
+
+import (
+    "sort"
+    "time"
+)
+
+/**
+Where a callback fits in the schedule a run would have had with unlimited concurrency and no scheduling overhead,
+given the time each callback function actually took. Offsets are counted from the start of the run
+ */
+type NodeAnalysis struct {
+    CallbackName   string
+    //time spent invoking the callback function, zero if it never was
+    Duration       time.Duration
+    //as soon as all its dependencies are over
+    EarliestStart  time.Duration
+    EarliestFinish time.Duration
+    //as late as possible without delaying the end of the run
+    LatestStart    time.Duration
+    LatestFinish   time.Duration
+    //how much the callback can be delayed or slowed down without delaying the end of the run
+    Slack          time.Duration
+    //whether it has no slack
+    Critical       bool
+    //whether it lies on every critical path, so that making it faster makes the whole run faster
+    Bottleneck     bool
+}
+
+/**
+Critical path and slack of each callback of a finished run
+ */
+type CriticalPathAnalysis struct {
+    //duration of the longest chain of dependent callbacks : how long the run lasts with unlimited concurrency
+    Makespan     time.Duration
+    //names of a longest chain of dependent callbacks, from the first one to run to the last one
+    CriticalPath []string
+    Nodes        map[string]*NodeAnalysis
+}
+
+/**
+Compute the critical path of a run of the plan from the time each callback function took, as told by the report.
+Callbacks missing from the report or which were never invoked take no time
+ */
+func (plan *Plan) AnalyzeRun(report *RunReport) *CriticalPathAnalysis {
+    durations := map[string]time.Duration{}
+    for callbackName, outcome := range report.Outcomes {
+        durations[callbackName] = outcome.Duration()
+    }
+    return plan.analyze(durations)
+}
+
+func (plan *Plan) analyze(durations map[string]time.Duration) *CriticalPathAnalysis {
+    analysis := &CriticalPathAnalysis{Nodes: map[string]*NodeAnalysis{}}
+    order := plan.topologicalOrder()
+    //forward pass : earliest times
+    for _, callbackName := range order {
+        node := &NodeAnalysis{CallbackName: callbackName, Duration: durations[callbackName]}
+        for _, dependencyName := range plan.nodes[callbackName].dependencies {
+            node.EarliestStart = max(node.EarliestStart, analysis.Nodes[dependencyName].EarliestFinish)
+        }
+        node.EarliestFinish = node.EarliestStart + node.Duration
+        analysis.Makespan = max(analysis.Makespan, node.EarliestFinish)
+        analysis.Nodes[callbackName] = node
+    }
+    //backward pass : latest times
+    for position := len(order) - 1; position >= 0; position-- {
+        node := analysis.Nodes[order[position]]
+        node.LatestFinish = analysis.Makespan
+        for _, dependantName := range plan.nodes[node.CallbackName].dependants {
+            node.LatestFinish = min(node.LatestFinish, analysis.Nodes[dependantName].LatestStart)
+        }
+        node.LatestStart = node.LatestFinish - node.Duration
+        node.Slack = node.LatestStart - node.EarliestStart
+        node.Critical = node.Slack == 0
+    }
+    //while a critical callback runs, any other one running at the same time is on another critical path
+    for _, node := range analysis.Nodes {
+        node.Bottleneck = node.Critical && node.Duration > 0
+        for _, other := range analysis.Nodes {
+            if (node.Bottleneck && other != node && other.Critical && other.Duration > 0 &&
+                other.EarliestStart < node.EarliestFinish && node.EarliestStart < other.EarliestFinish) {
+                node.Bottleneck = false
+            }
+        }
+    }
+    analysis.CriticalPath = analysis.criticalPath(plan, order)
+    return analysis
+}
+
+/**
+Walk back from the critical callback finishing last, the one coming last in the topological order among equals,
+through the critical dependencies it waited for, ties being broken by name
+ */
+func (analysis *CriticalPathAnalysis) criticalPath(plan *Plan, order []string) []string {
+    var last *NodeAnalysis
+    for position := len(order) - 1; position >= 0 && last == nil; position-- {
+        if node := analysis.Nodes[order[position]]; node.Critical && node.EarliestFinish == analysis.Makespan {
+            last = node
+        }
+    }
+    var path []string
+    for last != nil {
+        path = append(path, last.CallbackName)
+        var previous *NodeAnalysis
+        for _, dependencyName := range plan.nodes[last.CallbackName].dependencies {
+            dependency := analysis.Nodes[dependencyName]
+            if (dependency.Critical && dependency.EarliestFinish == last.EarliestStart) {
+                previous = dependency
+                break
+            }
+        }
+        last = previous
+    }
+    for i, j := 0, len(path) - 1; i < j; i, j = i + 1, j - 1 {
+        path[i], path[j] = path[j], path[i]
+    }
+    return path
+}
+
+/**
+Return the names of the callbacks which make the whole run faster when made faster, the slowest first
+ */
+func (analysis *CriticalPathAnalysis) Bottlenecks() []string {
+    var bottlenecks []*NodeAnalysis
+    for _, node := range analysis.Nodes {
+        if (node.Bottleneck) {
+            bottlenecks = append(bottlenecks, node)
+        }
+    }
+    sort.Slice(bottlenecks, func(i, j int) bool {
+        if (bottlenecks[i].Duration != bottlenecks[j].Duration) {
+            return bottlenecks[i].Duration > bottlenecks[j].Duration
+        }
+        return bottlenecks[i].CallbackName < bottlenecks[j].CallbackName
+    })
+    callbackNames := make([]string, 0, len(bottlenecks))
+    for _, node := range bottlenecks {
+        callbackNames = append(callbackNames, node.CallbackName)
+    }
+    return callbackNames
+}
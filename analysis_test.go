@@ -0,0 +1,60 @@
+package channeler
+
+import (
+    "testing"
+    "time"
+    "github.com/stretchr/testify/assert"
+)
+
+/**
+Graph of TestChanneler_RunAllOkIn11s along with a jam made of the bananas and the cherry
+ */
+func fruitsPlan(t *testing.T) *Plan {
+    channelerInstance := NewChanneler(&CallbackChain{
+        "getRedApple": NewChanneledCallback(noopCallback, []string{}),
+        "getGreenApple": NewChanneledCallback(noopCallback, []string{}),
+        "getYellowApple": NewChanneledCallback(noopCallback, []string{}),
+        "getYellowBanana": NewChanneledCallback(noopCallback, []string{"getGreenApple", "getYellowApple"}),
+        "getGreenBanana": NewChanneledCallback(noopCallback, []string{"getGreenApple", "getYellowApple"}),
+        "getRedCherry": NewChanneledCallback(noopCallback, []string{"getRedApple"}),
+        "makeJam": NewChanneledCallback(noopCallback, []string{"getYellowBanana", "getGreenBanana", "getRedCherry"}),
+    })
+    plan, err := channelerInstance.Compile()
+    assert.Nil(t, err)
+    return plan
+}
+
+func TestPlan_AnalyzeRun(t *testing.T) {
+    analysis := fruitsPlan(t).AnalyzeRun(fruitsReport())
+    assert.Equal(t, 11 * time.Second, analysis.Makespan)
+    assert.Equal(t, []string{"getGreenApple", "getGreenBanana", "makeJam"}, analysis.CriticalPath)
+    assert.Equal(t, []string{"getGreenApple", "getGreenBanana"}, analysis.Bottlenecks())
+
+    assert.Equal(t, &NodeAnalysis{
+        CallbackName: "getRedCherry",
+        Duration: 6 * time.Second,
+        EarliestStart: 1 * time.Second,
+        EarliestFinish: 7 * time.Second,
+        LatestStart: 5 * time.Second,
+        LatestFinish: 11 * time.Second,
+        Slack: 4 * time.Second,
+    }, analysis.Nodes["getRedCherry"])
+    assert.Equal(t, 3 * time.Second, analysis.Nodes["getYellowApple"].Slack)
+    assert.Equal(t, 1 * time.Second, analysis.Nodes["getYellowBanana"].Slack)
+    //makeJam never ran : it is on the critical path but takes no time
+    assert.True(t, analysis.Nodes["makeJam"].Critical)
+    assert.False(t, analysis.Nodes["makeJam"].Bottleneck)
+}
+
+/**
+When two chains are equally long, speeding up only one of them does not shorten the run
+ */
+func TestPlan_AnalyzeRunParallelCriticalPaths(t *testing.T) {
+    report := fruitsReport()
+    report.Outcomes["getYellowApple"].FinishedAt = report.StartedAt.Add(6 * time.Second)
+    analysis := fruitsPlan(t).AnalyzeRun(report)
+    assert.Equal(t, 11 * time.Second, analysis.Makespan)
+    assert.True(t, analysis.Nodes["getYellowApple"].Critical)
+    assert.True(t, analysis.Nodes["getGreenApple"].Critical)
+    assert.Equal(t, []string{"getGreenBanana"}, analysis.Bottlenecks())
+}
@@ -1,8 +1,11 @@
 package channeler
 
-/*import (
-    "log"
-)*/
+import (
+    "context"
+    "errors"
+    "reflect"
+    "time"
+)
 
 
 /**
@@ -13,50 +16,238 @@ type ChanneledCallback struct {
     //keys of Channeler.CallbackChain that need to terminate before CallbackFunction is allowed to run
     //if empty then the ChanneledCallback does not have any pre-requisite and can be ran immediately
     DependenciesNames []string
+    //per dependency name options, dependencies without options are required
+    DependenciesOptions map[string]DependencyOptions
     //callbacks to execute, with various parameters number and types. Expect to return a value of various type
     //and an error. The passed parameters will be result fetched from dependenciesChannels.
     //this is this function's job to cast the interfaces mapped by variadic args appropriately
     CallbackFunction  ChanneledCallbackCallbackFunction
-    /**
-    => channels
-        //this ChanneledCallback's dependencies channels
-        => "dependencies" : type channelsMap : contains channels that may be fed by results or errors of callbacks corresponding to names defined in DependenciesNames
-        //inverse of "dependencies" : channels in which this ChanneledCallback might write errors or results in order to inform other ChanneledCallback that depend on it
-        => "feed" : type channelsMap : contains channels to feed with this ChanneledCallback.CallbackFunction's eventual returned result or error
-     */
-    channels          map[string]channelsMap
+    //same as CallbackFunction, but also receiving the run's context : takes precedence over CallbackFunction when set
+    ContextCallbackFunction ChanneledCallbackContextFunction
+    //maximum execution time of the callback function, waiting for dependencies excluded. Once exceeded, the callback's context
+    //is cancelled and a TimeoutError is propagated without waiting for the function to return. Zero means no timeout
+    Timeout           time.Duration
+    //how the callback function is tried again when it fails, nil means that it is invoked only once
+    Retry             *RetryPolicy
+    //wrap the invocation of the callback function, inside the Channeler's Interceptors, the first one being the outermost
+    Interceptors      []Interceptor
 }
 
 /**
-Propagate a given message or error to channeledCallback.channels["feed"] and close each one of these channels
+Tune how a ChanneledCallback depends on one of its DependenciesNames
  */
-func (channeledCallback *ChanneledCallback) propagateToFedChannels(message interface{}) {
-    if (len(channeledCallback.channels["feed"]) > 0) {
-        //feed result or errors to dependencies
-        for _, fedChannel := range channeledCallback.channels["feed"] {
-            //log.Println("FEED CHANNEL %s WITH %s", fedName, message)
-            fedChannel <- message
+type DependencyOptions struct {
+    //the callback still runs when this dependency fails, receiving a FailedDependency holding the dependency's error
+    //in its CallbackResults instead of the dependency's result
+    Optional bool
+    //the callback still runs when this dependency fails with a partial result (a non zero result along with its error),
+    //receiving a PartialResult in its CallbackResults instead of the dependency's result
+    AcceptPartial bool
+}
+
+/**
+Given to a callback in place of the result of a dependency which failed with a partial result, when it is accepted
+(see DependencyOptions.AcceptPartial). Get() and CallbackResults.Err() see through it
+ */
+type PartialResult struct {
+    Value interface{}
+    Err   error
+}
+
+/**
+Given to a callback in place of the result of an optional dependency which failed (see DependencyOptions.Optional),
+so that it cannot be mistaken for a result which happens to be an error. CallbackResults.Err() sees through it
+ */
+type FailedDependency struct {
+    Err error
+}
+
+/**
+Mark some of the DependenciesNames as optional, so that the callback function still runs when they fail.
+Return the ChanneledCallback itself so that it can be chained with its factory method
+ */
+func (channeledCallback *ChanneledCallback) WithOptionalDependencies(dependenciesNames ...string) *ChanneledCallback {
+    return channeledCallback.withDependenciesOptions(dependenciesNames, func(dependencyOptions *DependencyOptions) {
+        dependencyOptions.Optional = true
+    })
+}
+
+/**
+Let the callback function consume the partial results of some of the DependenciesNames when they fail.
+Return the ChanneledCallback itself so that it can be chained with its factory method
+ */
+func (channeledCallback *ChanneledCallback) WithPartialDependencies(dependenciesNames ...string) *ChanneledCallback {
+    return channeledCallback.withDependenciesOptions(dependenciesNames, func(dependencyOptions *DependencyOptions) {
+        dependencyOptions.AcceptPartial = true
+    })
+}
+
+func (channeledCallback *ChanneledCallback) withDependenciesOptions(dependenciesNames []string, setOption func(dependencyOptions *DependencyOptions)) *ChanneledCallback {
+    if (channeledCallback.DependenciesOptions == nil) {
+        channeledCallback.DependenciesOptions = map[string]DependencyOptions{}
+    }
+    for _, dependencyName := range dependenciesNames {
+        dependencyOptions := channeledCallback.DependenciesOptions[dependencyName]
+        setOption(&dependencyOptions)
+        channeledCallback.DependenciesOptions[dependencyName] = dependencyOptions
+    }
+    return channeledCallback
+}
+
+/**
+Whether a result returned along with an error is a partial one : a nil or zero value, such as a nil slice or pointer
+returned as an interface{}, is not
+ */
+func isPartialResult(result interface{}) bool {
+    return result != nil && !reflect.ValueOf(result).IsZero()
+}
+
+/**
+Compute what a dependency's result and error look like from the callback's point of view : the value to put
+in its CallbackResults, and whether the failure of the dependency prevents the callback from running
+ */
+func (channeledCallback *ChanneledCallback) receive(dependencyName string, received envelope) (interface{}, bool) {
+    if (received.err == nil) {
+        return received.value, false
+    }
+    dependencyOptions := channeledCallback.DependenciesOptions[dependencyName]
+    if (dependencyOptions.AcceptPartial && isPartialResult(received.value)) {
+        return PartialResult{received.value, received.err}, false
+    }
+    return FailedDependency{received.err}, !dependencyOptions.Optional
+}
+
+/**
+Wait for the dependencies in the order they finish through the inbox they all feed, until they are all over,
+one of the required ones failed, or ctx is done.
+Return the results received so far, and the errors of the required dependencies which failed by dependency name :
+once one of them failed, the other dependencies which are already over are taken into account without waiting for the rest
+ */
+func (channeledCallback *ChanneledCallback) awaitDependencies(ctx context.Context, inbox envelopeChannel) (CallbackResults, map[string]error) {
+    dependenciesResults := CallbackResults{}
+    failedDependencies := map[string]error{}
+    pending := len(channeledCallback.DependenciesNames)
+    accept := func(received envelope) {
+        var blocking bool
+        //a failed optional dependency is given to the callback function as a FailedDependency, an accepted partial result as a PartialResult
+        dependenciesResults[received.callbackName], blocking = channeledCallback.receive(received.callbackName, received)
+        if (blocking) {
+            failedDependencies[received.callbackName] = received.err
+        }
+        pending--
+    }
+    for pending > 0 && len(failedDependencies) == 0 {
+        select {
+        case received := <-inbox:
+            accept(received)
+        case <-ctx.Done():
+            return dependenciesResults, failedDependencies
+        }
+    }
+    //collect the failures which arrived along with the first one
+    for pending > 0 {
+        select {
+        case received := <-inbox:
+            accept(received)
+        default:
+            return dependenciesResults, failedDependencies
         }
     }
+    return dependenciesResults, failedDependencies
+}
+
+/**
+Propagate a given result and error to each of the "feed" channels of a ChanneledCallback
+ */
+func (feedChannels channelsMap) propagate(message envelope) {
+    //feed result or errors to dependencies
+    for _, fedChannel := range feedChannels {
+        fedChannel <- message
+    }
+}
+
+/**
+Call ContextCallbackFunction, or CallbackFunction if the former is not set, as many times as channeledCallback.Retry allows,
+through the given interceptors then through the callback's own Interceptors.
+Return the result and error of the last attempt along with the errors of every failed attempt.
+Errors due to ctx being done are returned as is for the caller to handle them, and panics are returned as PanicError
+unless crashOnPanic is set
+ */
+func (channeledCallback *ChanneledCallback) invoke(ctx context.Context, callbackName string, dependencies CallbackResults, interceptors []Interceptor, crashOnPanic bool) (interface{}, []error, error) {
+    callbackFunction := channeledCallback.ContextCallbackFunction
+    if (callbackFunction == nil) {
+        callbackFunction = channeledCallback.CallbackFunction.WithContext()
+    }
+    if (!crashOnPanic) {
+        callbackFunction = recoverPanics(callbackName, callbackFunction)
+    }
+    //interceptors may call their next Invoker several times
+    var attemptsErrors []error
+    invoker := intercept(func(ctx context.Context, callbackName string, dependencies CallbackResults) (interface{}, error) {
+        result, retryAttemptsErrors, err := channeledCallback.Retry.do(ctx, func(attemptCtx context.Context) (interface{}, error) {
+            return channeledCallback.attempt(attemptCtx, callbackName, callbackFunction, dependencies)
+        })
+        attemptsErrors = append(attemptsErrors, retryAttemptsErrors...)
+        return result, err
+    }, append(append([]Interceptor{}, interceptors...), channeledCallback.Interceptors...)...)
+    interceptedFunction := func(ctx context.Context, dependencies CallbackResults) (interface{}, error) {
+        return invoker(ctx, callbackName, dependencies)
+    }
+    if (!crashOnPanic && len(interceptors) + len(channeledCallback.Interceptors) > 0) {
+        interceptedFunction = recoverPanics(callbackName, interceptedFunction)
+    }
+    result, err := interceptedFunction(ctx, dependencies)
+    return result, attemptsErrors, err
 }
 
 /**
-Close each of channeler.channels and each of channeler.CallbackChain channels
+Call the callback function once, within channeledCallback.Timeout if any
  */
-func (channeledCallback *ChanneledCallback) closeAllChannels() {
-    for _, oneChannel := range channeledCallback.channels["feed"] {
-        //log.Println("  - Close channeledCallback's feed channel %s", feedCallbackName)
-        close(oneChannel)
+func (channeledCallback *ChanneledCallback) attempt(ctx context.Context, callbackName string, callbackFunction ChanneledCallbackContextFunction, dependencies CallbackResults) (interface{}, error) {
+    if (channeledCallback.Timeout <= 0) {
+        return callbackFunction(ctx, dependencies)
+    }
+    startedAt := time.Now()
+    callbackCtx, cancel := context.WithTimeout(ctx, channeledCallback.Timeout)
+    defer cancel()
+    type returnedValues struct {
+        result interface{}
+        err    error
+    }
+    //buffered so that a callback returning after its timeout does not leak its goroutine
+    returnedChannel := make(chan returnedValues, 1)
+    go func() {
+        result, err := callbackFunction(callbackCtx, dependencies)
+        returnedChannel <- returnedValues{result, err}
+    }()
+    select {
+    case returned := <-returnedChannel:
+        if (returned.err != nil && ctx.Err() == nil && callbackCtx.Err() != nil && errors.Is(returned.err, context.DeadlineExceeded)) {
+            return nil, &TimeoutError{callbackName, time.Since(startedAt)}
+        }
+        return returned.result, returned.err
+    case <-callbackCtx.Done():
+        if (ctx.Err() != nil) {
+            return nil, ctx.Err()
+        }
+        return nil, &TimeoutError{callbackName, time.Since(startedAt)}
     }
 }
 
 /**
-Initialize channeledCallback(s channels properties to empty map
+Whether a TimeoutError found in the error of a callback comes from the callback's own Timeout, rather than e.g. from
+a nested Channeler ran by its callback function
+ */
+func (channeledCallback *ChanneledCallback) timedOut(callbackName string, timeoutErr *TimeoutError) bool {
+    return channeledCallback.Timeout > 0 && timeoutErr.CallbackName == callbackName
+}
+
+/**
+Adapt a callback function which does not care about the run's context to the ChanneledCallbackContextFunction signature
  */
-func (channeledCallback *ChanneledCallback) initDependenciesFeedChannels() {
-    channeledCallback.channels = map[string]channelsMap{
-        "dependencies": channelsMap{},
-        "feed":channelsMap{},
+func (callbackFunction ChanneledCallbackCallbackFunction) WithContext() ChanneledCallbackContextFunction {
+    return func(ctx context.Context, dependencies CallbackResults) (interface{}, error) {
+        return callbackFunction(dependencies)
     }
 }
 
@@ -70,3 +261,14 @@ func NewChanneledCallback(callbackFunction ChanneledCallbackCallbackFunction, de
     }
     return channeledCallback
 }
+
+/**
+Initializes a new ChanneledCallback whose function receives the run's context, with passed public properties
+ */
+func NewContextChanneledCallback(contextCallbackFunction ChanneledCallbackContextFunction, dependenciesNames []string) *ChanneledCallback {
+    channeledCallback := &ChanneledCallback{
+        DependenciesNames: dependenciesNames,
+        ContextCallbackFunction: contextCallbackFunction,
+    }
+    return channeledCallback
+}
@@ -1,25 +1,221 @@
 package channeler
 
 import (
+    "context"
     "fmt"
-    //"log"
-    "github.com/julianguinard/go-channeler/utils/array"
+    "log/slog"
+    "sort"
+    "strings"
+    "time"
 )
 
-type variadicTypeChannel chan interface{}
+/**
+What a callback sends to its dependent callbacks and to the Channeler once it is over : its result and its error are carried
+separately so that a result which happens to be an error value is not mistaken for a failure, and so that a callback
+returning both a result and an error keeps both
+ */
+type envelope struct {
+    //name of the callback which sent it, as dependent callbacks receive the envelopes of all their dependencies through a single inbox
+    callbackName string
+    value        interface{}
+    err          error
+}
+type envelopeChannel chan envelope
 //this "map type"'s key is a callback name in a Channeler's CallbackChain and the value is a channel of variable value
-type channelsMap map[string]variadicTypeChannel
+type channelsMap map[string]envelopeChannel
 type CallbackResults map[string]interface{}
 
+/**
+Return the error of a dependency which failed, received as a FailedDependency or along with a PartialResult, nil otherwise :
+a result which happens to be an error value is not a failure
+ */
+func (callbackResults CallbackResults) Err(callbackName string) error {
+    switch received := callbackResults[callbackName].(type) {
+    case FailedDependency:
+        return received.Err
+    case PartialResult:
+        return received.Err
+    }
+    return nil
+}
+
 type CallbackChain map[string]*ChanneledCallback
 type ChanneledCallbackCallbackFunction func(dependencies CallbackResults) (interface{}, error)
+type ChanneledCallbackContextFunction func(ctx context.Context, dependencies CallbackResults) (interface{}, error)
 
+/**
+Stored for callbacks which were not ran because some of their required dependencies failed.
+Path is the chain of callbacks through which the failure reached CallbackName, starting with the callback which failed
+on its own (e.g. getYellowApple -> getGreenBanana) and following the first of FailedDependencies when there are several.
+Cause is the error of the first callback of Path, and Unwrap gives access to the errors of all FailedDependencies
+so that errors.Is and errors.As can find any of the root causes
+ */
 type DependencyError struct {
     CallbackName string
-    FailedDependency string
+    //sorted names of the required dependencies known to have failed when CallbackName stopped waiting for them
+    FailedDependencies []string
+    //errors of FailedDependencies, in the same order
+    Errs []error
+    Path []string
+    Cause error
 }
 func(err *DependencyError) Error() string {
-    return fmt.Sprintf("%s failed dependency that must must be propagated in %s", err.CallbackName, err.FailedDependency)
+    return fmt.Sprintf("%s could not run because %s failed (%s) : %s",
+        err.CallbackName, strings.Join(err.FailedDependencies, ", "), strings.Join(err.Path, " -> "), err.Cause)
+}
+func(err *DependencyError) Unwrap() []error {
+    return err.Errs
+}
+
+/**
+Return the sorted names of the callbacks which failed on their own and led to this error, through any of FailedDependencies
+ */
+func(err *DependencyError) RootCauses() []string {
+    rootCauses := map[string]bool{}
+    for position, dependencyErr := range err.Errs {
+        upstreamErr := propagatedBy(err.FailedDependencies[position], dependencyErr)
+        if (upstreamErr == nil) {
+            rootCauses[err.FailedDependencies[position]] = true
+            continue
+        }
+        for _, rootCause := range upstreamErr.RootCauses() {
+            rootCauses[rootCause] = true
+        }
+    }
+    callbackNames := make([]string, 0, len(rootCauses))
+    for callbackName := range rootCauses {
+        callbackNames = append(callbackNames, callbackName)
+    }
+    sort.Strings(callbackNames)
+    return callbackNames
+}
+
+/**
+Stored for callbacks that were not ran, or could not finish, because the run's context was done.
+Unwrap gives access to the context's cause (context.Canceled, context.DeadlineExceeded...)
+ */
+type CancelledError struct {
+    CallbackName string
+    Cause error
+}
+func(err *CancelledError) Error() string {
+    return fmt.Sprintf("%s was cancelled : %s", err.CallbackName, err.Cause)
+}
+func(err *CancelledError) Unwrap() error {
+    return err.Cause
+}
+
+/**
+Stored for callbacks which exceeded their own ChanneledCallback.Timeout, or which could not finish before
+the Channeler.Timeout deadline. Elapsed is measured from the callback's start, or from the run's start for the latter.
+It unwraps to context.DeadlineExceeded
+ */
+type TimeoutError struct {
+    CallbackName string
+    Elapsed time.Duration
+}
+func(err *TimeoutError) Error() string {
+    return fmt.Sprintf("%s timed out after %s", err.CallbackName, err.Elapsed)
+}
+func(err *TimeoutError) Unwrap() error {
+    return context.DeadlineExceeded
+}
+
+type ErrorPolicy int
+
+const (
+    //a failing callback only prevents its dependent callbacks from running, independent branches keep running
+    ContinueOnError ErrorPolicy = iota
+    //the first failing callback cancels the whole run : running callbacks get their context cancelled,
+    //pending ones are never started, and they are all marked with a CancelledError caused by a FailFastError
+    FailFast
+)
+
+/**
+Cause of the cancellation of a run stopped by its first failing callback, when the Channeler's ErrorPolicy is FailFast.
+Unwrap gives access to the callback's error
+ */
+type FailFastError struct {
+    CallbackName string
+    Err error
+}
+func(err *FailFastError) Error() string {
+    return fmt.Sprintf("run stopped because %s failed : %s", err.CallbackName, err.Err)
+}
+func(err *FailFastError) Unwrap() error {
+    return err.Err
+}
+
+/**
+Returned by Run() when some callbacks did not succeed. Failures holds the outcome of each of them, sorted by callback name :
+RootFailures() are the ones which failed or timed out on their own, PropagatedFailures() the ones which were not ran,
+or could not finish, because of them or because the run was stopped. Cause tells why the run was stopped before all
+callbacks were over (the context's cause, or a FailFastError), nil if it was not.
+Unwrap gives access to Cause and to the error of each failure, so that errors.Is and errors.As can find any of them
+ */
+type RunError struct {
+    Cause    error
+    Failures []*NodeOutcome
+}
+func(err *RunError) Error() string {
+    var messages []string
+    if (err.Cause != nil) {
+        messages = append(messages, fmt.Sprintf("run stopped : %s", err.Cause))
+    }
+    for _, failure := range err.Failures {
+        messages = append(messages, fmt.Sprintf("%s %s : %s", failure.CallbackName, failure.Status, failure.Err))
+    }
+    return strings.Join(messages, "\n")
+}
+func(err *RunError) Unwrap() []error {
+    var errs []error
+    if (err.Cause != nil) {
+        errs = append(errs, err.Cause)
+    }
+    for _, failure := range err.Failures {
+        errs = append(errs, failure.Err)
+    }
+    return errs
+}
+
+/**
+Failures of the callbacks which failed or timed out on their own
+ */
+func(err *RunError) RootFailures() []*NodeOutcome {
+    var failures []*NodeOutcome
+    for _, failure := range err.Failures {
+        if (failure.Status == Failed || failure.Status == TimedOut) {
+            failures = append(failures, failure)
+        }
+    }
+    return failures
+}
+
+/**
+Failures of the callbacks which were not ran or could not finish because of other failures, or because the run was stopped
+ */
+func(err *RunError) PropagatedFailures() []*NodeOutcome {
+    var failures []*NodeOutcome
+    for _, failure := range err.Failures {
+        if (failure.Status != Failed && failure.Status != TimedOut) {
+            failures = append(failures, failure)
+        }
+    }
+    return failures
+}
+
+/**
+Build the error returned by Run() from its report and the reason why it was stopped early if it was, nil if every callback succeeded
+ */
+func newRunError(report *RunReport, cause error) error {
+    runErr := &RunError{Cause: cause}
+    for _, callbackName := range report.CallbackNames(Failed, UpstreamFailed, Skipped, Cancelled, TimedOut) {
+        runErr.Failures = append(runErr.Failures, report.Outcomes[callbackName])
+    }
+    if (runErr.Cause == nil && len(runErr.Failures) == 0) {
+        return nil
+    }
+    return runErr
 }
 
 /*
@@ -28,11 +224,32 @@ appropriate channel chain
  */
 type Channeler struct {
     CallbackChain     *CallbackChain
-    //========private attribute : initialized on NewChanneler() call
-    channels          channelsMap
-    //populated from CallbackChain : a channel by map entry in CallbackChain
+    //deadline for the whole run, measured from Run() call : callbacks which did not finish by then get a TimeoutError
+    //and Run() returns. Zero means no deadline
+    Timeout           time.Duration
+    //maximum number of callback functions running at the same time, zero means no limit. A slot is freed as soon as
+    //its callback is over, including when it timed out or the run was stopped : a callback function ignoring its context
+    //keeps running in the background, so more functions than this may actually be running at once
+    MaxConcurrency    int
+    //picks which ready callback gets the next free slot when MaxConcurrency is reached, FirstReadyFirst if nil
+    ReadyQueueOrder   ReadyQueueOrder
+    //let panics of callback functions crash the process instead of storing them as PanicError
+    CrashOnPanic      bool
+    //what happens to the rest of the callback chain when a callback fails, ContinueOnError by default
+    ErrorPolicy       ErrorPolicy
+    //wrap the invocation of every callback function, outside the callbacks' own Interceptors, the first one being the outermost
+    Interceptors      []Interceptor
+    //functions called when each callback starts and is over
+    Hooks             Hooks
+    //receives structured records of the scheduler's decisions, each with the run's ID, nothing is logged if nil
+    Logger            *slog.Logger
+    //starts a span for each run and a child span for each of its callbacks, no span is started if nil
+    Tracer            Tracer
+    //populated from the RunReport of the last Run() : an entry by map entry in CallbackChain
     Results           CallbackResults
     Errors            map[string]error
+    //errors of each failed attempt of the callbacks, in order. Only set for callbacks which failed at least once
+    AttemptsErrors    map[string][]error
 }
 
 /**
@@ -43,121 +260,97 @@ func NewChanneler(callbackChain *CallbackChain) *Channeler {
     return channeler
 }
 
-func (channeler *Channeler) reset() {
-    channeler.Results = CallbackResults{}
-    channeler.Errors = map[string]error{}
-    channeler.channels = channelsMap{}
-}
-
-func (channeler *Channeler) establishDependencyChannels() {
-    channeler.reset()
-    //start by resetting all dependencies channels in callback chain
-    for _, channeledCallback := range *channeler.CallbackChain {
-        channeledCallback.initDependenciesFeedChannels()
-        //remove inexisting dependencies
-    }
-    for callbackName, channeledCallback := range *channeler.CallbackChain {
-        channeler.channels[callbackName] = make(variadicTypeChannel, 1)
-        //check if the function needs to feed channels (in case it is refered to as dependency by other ChanneledCallback
-        //in the callbackChain
-        for nameOfPotentiallyDependantCb, potentiallyDependantCb := range *channeler.CallbackChain {
-            //exclude recursive links (self-dependencies prohibited)
-            if (callbackName != nameOfPotentiallyDependantCb && (array.ArraySearchString(potentiallyDependantCb.DependenciesNames, callbackName) != -1)) {
-                //log.Println(nameOfPotentiallyDependantCb + " is dependent on " + callbackName)
-                //establish a dependency channel that will be fetched into current callbackFunction's feedChannels attribute,
-                //and into potentiallyDependantCb's dependenciesChannels
-                channeledCallback.channels["feed"][nameOfPotentiallyDependantCb] = make(variadicTypeChannel, 1)
-                potentiallyDependantCb.channels["dependencies"][callbackName] = channeledCallback.channels["feed"][nameOfPotentiallyDependantCb]
-                /*log.Printf("EXPOSE A variadicTypeChannel DEPENDENCY BETWEEN channeledCallback.channels[\"feed\"][\"%s\"] (Adress : %s) and potentiallyDependantCb.channels[\"dependencies\"][\"%s\"] (Adress : %s)",
-                    nameOfPotentiallyDependantCb,
-                    channeledCallback.channels["feed"][nameOfPotentiallyDependantCb],
-                    callbackName,
-                    potentiallyDependantCb.channels["dependencies"][callbackName],
-                )*/
-            }
+/**
+Launch all callbacks simultaneously (1 goroutine per callback in channeler.CallbackChain)
+and block them according to their dependencies using their channels.
+The CallbackChain is compiled into a Plan first : nothing is ran and the Validate() error is returned if the graph is misconfigured.
+Otherwise a RunReport holding the outcome of every callback is returned, along with a RunError if some of them did not succeed.
+As the Results, Errors and AttemptsErrors of the Channeler are replaced by each run, concurrent runs must use a Plan instead
+ */
+func (channeler *Channeler) Run() (*RunReport, error) {
+    return channeler.RunContext(context.Background())
+}
+
+/**
+Same as Run(), but the whole callback chain is bound to ctx : callbacks receive it (or a context derived from it)
+and once it is done, callbacks that did not start yet are marked with a CancelledError instead of being invoked.
+RunContext returns as soon as ctx is done, without waiting for in-flight callbacks ignoring it, and the cause of ctx being done
+(see context.Cause) is then the Cause of the returned RunError
+ */
+func (channeler *Channeler) RunContext(ctx context.Context) (*RunReport, error) {
+    plan, err := channeler.Compile()
+    if (err != nil) {
+        return nil, err
+    }
+    report, err := plan.RunContext(ctx)
+    channeler.storeReport(report)
+    //...from now on then all results must be accessible from channeler.Results
+    return report, err
+}
+
+/**
+Expose the outcomes of a run through channeler.Results, channeler.Errors and channeler.AttemptsErrors
+ */
+func (channeler *Channeler) storeReport(report *RunReport) {
+    channeler.Results = report.Results()
+    channeler.Errors = report.Errors()
+    channeler.AttemptsErrors = map[string][]error{}
+    for callbackName, outcome := range report.Outcomes {
+        if (len(outcome.AttemptsErrors) > 0) {
+            channeler.AttemptsErrors[callbackName] = outcome.AttemptsErrors
         }
     }
-    //log.Println(channeler.channels)
 }
 
 /**
-Close each of channeler.channels and each of channeler.CallbackChain channels
+Error stored for a callback which could not run or finish because the run's context is done :
+a TimeoutError when its deadline is exceeded, a CancelledError otherwise
  */
-func (channeler *Channeler) closeAllChannels() {
-    //close the channeler's own channels
-    for _, oneChannel := range channeler.channels {
-        //log.Printf("Close channeler's channel %s", callbackName)
-        close(oneChannel)
+func interruption(ctx context.Context, callbackName string, runStartedAt time.Time) error {
+    if (ctx.Err() == context.DeadlineExceeded) {
+        return &TimeoutError{callbackName, time.Since(runStartedAt)}
     }
-    //close the channeler's callback chain channels
-    for _, oneChanneledCallback := range *channeler.CallbackChain {
-        if(len(oneChanneledCallback.channels["feed"]) > 0) {
-            //log.Printf("- Close all feed channels for channeler's callback %s", callbackName)
-            oneChanneledCallback.closeAllChannels()
-        }
+    return &CancelledError{callbackName, context.Cause(ctx)}
+}
+
+/**
+Error stored for a callback which could not run because some of its required dependencies failed
+ */
+func upstreamFailure(callbackName string, failedDependencies map[string]error) *DependencyError {
+    dependencyErr := &DependencyError{CallbackName: callbackName, FailedDependencies: failedDependenciesNames(failedDependencies)}
+    for _, dependencyName := range dependencyErr.FailedDependencies {
+        dependencyErr.Errs = append(dependencyErr.Errs, failedDependencies[dependencyName])
+    }
+    //a failed dependency which could not run either holds the beginning of the path
+    if upstreamErr := propagatedBy(dependencyErr.FailedDependencies[0], dependencyErr.Errs[0]); upstreamErr != nil {
+        dependencyErr.Path = append(append([]string{}, upstreamErr.Path...), callbackName)
+        dependencyErr.Cause = upstreamErr.Cause
+    } else {
+        dependencyErr.Path = []string{dependencyErr.FailedDependencies[0], callbackName}
+        dependencyErr.Cause = dependencyErr.Errs[0]
     }
+    return dependencyErr
 }
 
 /**
-Launch all callbacks simultaneously (1 goroutine per callback in channeler.CallbackChain)
-and block them according to their dependencies using their channels
- */
-func (channeler *Channeler) Run() {
-    channeler.establishDependencyChannels()
-    for callbackName, channeledCallback := range *channeler.CallbackChain {
-        go func(callbackName string, channeledCallback *ChanneledCallback, resultChannel variadicTypeChannel) {
-            var err error
-            var result interface{}
-            dependenciesResults := CallbackResults{}
-            //if there are blocking dependencies wait for them to be fetched using dependenciesChannels...
-            if (len(channeledCallback.channels["dependencies"]) > 0) {
-                //log.Printf("[%s] -- needs to wait for %d dependencies to be satisfied...", callbackName, len(channeledCallback.channels["dependencies"]))
-                /*for depCbName, _ := range channeledCallback.channels["dependencies"] {
-                    log.Printf("[%s] --    - callback %s", callbackName, depCbName)
-                }*/
-                for depCbName, dependencyCbChannel := range channeledCallback.channels["dependencies"] {
-                    dependenciesResults[depCbName] = <-dependencyCbChannel
-                    //whenever an error is received through a dependency channel, we do not invoke the channeledCallback.CallbackFunction
-                    //as the dependencies could not be fullfilled.
-                    if receivedError, isOfTypeError := dependenciesResults[depCbName].(error); isOfTypeError {
-                        //log.Printf("[%s] -- RECEVIED AN ERROR FROM ITS %s DEPENDENCY!! cannot call the callback function, propagate the error to feed dependencies...", callbackName, depCbName)
-                        err = receivedError
-                        break
-                    }
-                }
-            }
-            if(err == nil) {
-                //...then call the CallbackFunction along with the args from dependencies if any...
-                result, err = channeledCallback.CallbackFunction(dependenciesResults)
-                //log.Printf("[%s] -- HAS RETURNED result %s and error %s", callbackName, result, err)
-            }
-
-            if (err == nil) {
-                channeledCallback.propagateToFedChannels(result)
-                resultChannel <- result
-            } else {
-                channeledCallback.propagateToFedChannels(err)
-                resultChannel <- err
-            }
-            //log.Printf("============= END OF GOROUTINE %s==========================", callbackName)
-        }(callbackName, channeledCallback, channeler.channels[callbackName])
-    }
-
-    //wait for all results or errors to be gathered...
-    for callbackName, callbackChannel := range channeler.channels {
-        finalReceived := <-callbackChannel
-        //cast errors if received as we go
-        if receivedError, isOfTypeError := finalReceived.(error); isOfTypeError {
-            channeler.Errors[callbackName] = receivedError
-            channeler.Results[callbackName] = nil
-            //log.Printf("FINAL %s===> RECEIVED ERROR %s", callbackName, channeler.Errors[callbackName])
-        } else {
-            channeler.Results[callbackName] = finalReceived
-            channeler.Errors[callbackName] = nil
-            //log.Printf("FINAL %s===> RECEIVED RESULT %s", callbackName, finalReceived)
-        }
+Return err as a DependencyError if it is the one stored for callbackName because its own dependencies failed,
+nil if callbackName failed on its own
+ */
+func propagatedBy(callbackName string, err error) *DependencyError {
+    if dependencyErr, isDependencyErr := err.(*DependencyError); isDependencyErr && dependencyErr.CallbackName == callbackName {
+        return dependencyErr
     }
-    channeler.closeAllChannels()
-    //log.Printf("===================== ALL DONE, results %s =======================", channeler.Results)
-    //...from now on then all results must be accessible from channeler.Results
+    return nil
+}
+
+/**
+Return the sorted names of the failed dependencies, nil if there are none
+ */
+func failedDependenciesNames(failedDependencies map[string]error) []string {
+    var dependenciesNames []string
+    for dependencyName := range failedDependencies {
+        dependenciesNames = append(dependenciesNames, dependencyName)
+    }
+    sort.Strings(dependenciesNames)
+    return dependenciesNames
 }
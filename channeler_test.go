@@ -6,6 +6,7 @@ import (
     "testing"
     "fmt"
     "bytes"
+    "errors"
     "github.com/julianguinard/go-channeler/utils/strings"
     "github.com/stretchr/testify/assert"
     "github.com/spf13/cast"
@@ -42,7 +43,7 @@ func TestChanneler_RunRedAppleErrorPropagation(t *testing.T) {
 
     //override standard getRedApple ChanneledCallback in order to return an error that must propagate
     //to other callbacks dependent on getRedApple (in this case, getRedCherry)
-    redAppleErr := &(DependencyError{"getRedApple", "getRedCherry"})
+    redAppleErr := errors.New("getRedApple failed dependency that must be propagated in getRedCherry")
     (*channelerInstance.CallbackChain)["getRedApple"] = NewChanneledCallback(
         func(dependencies CallbackResults) (interface{}, error) {
             return nil, redAppleErr
@@ -92,7 +93,7 @@ func TestChanneler_RunYellowAppleErrorPropagation(t *testing.T) {
     channelerInstance := initFruitsChannelerWithStandardCbChain(t, waitTimePerFruitAndColor)
 
     //now check that breaking getYellowApple dependency also breaks getYellowBanana and getGreenBanana
-    yellowAppleErr := &DependencyError{"getYellowApple", "getYellowBanana and getGreenBanana"}
+    yellowAppleErr := errors.New("getYellowApple failed dependency that must be propagated in getYellowBanana and getGreenBanana")
     (*channelerInstance.CallbackChain)["getYellowApple"] = NewChanneledCallback(
         func(dependencies CallbackResults) (interface{}, error) {
             return nil, yellowAppleErr
@@ -142,13 +143,13 @@ func TestChanneler_RunRedAppleAndYellowAppleErrorPropagation(t *testing.T) {
 
     //override standard getRedApple ChanneledCallback in order to return an error that must propagate
     //to other callbacks dependent on getRedApple (in this case, getRedCherry)
-    redAppleErr := &(DependencyError{"getRedApple", "getRedCherry"})
+    redAppleErr := errors.New("getRedApple failed dependency that must be propagated in getRedCherry")
     (*channelerInstance.CallbackChain)["getRedApple"] = NewChanneledCallback(
         func(dependencies CallbackResults) (interface{}, error) {
             return nil, redAppleErr
         }, []string{},
     )
-    yellowAppleErr := &DependencyError{"getYellowApple", "getYellowBanana and getGreenBanana"}
+    yellowAppleErr := errors.New("getYellowApple failed dependency that must be propagated in getYellowBanana and getGreenBanana")
     (*channelerInstance.CallbackChain)["getYellowApple"] = NewChanneledCallback(
         func(dependencies CallbackResults) (interface{}, error) {
             return nil, yellowAppleErr
@@ -450,6 +451,7 @@ func checkResultNilAndErrorsSet(channelerInstance *Channeler, fruitAndColorSlice
     for _, oneFruitColorCouple := range fruitAndColorSlices {
         cbName := fmt.Sprintf("get%s%s", strings.Ucfirst(oneFruitColorCouple[1]), strings.Ucfirst(oneFruitColorCouple[0]))
         assert.Equal(t, nil, channelerInstance.Results[cbName])
-        assert.Equal(t, searchedError, channelerInstance.Errors[cbName])
+        //dependent callbacks get a DependencyError wrapping the error of the callback which failed
+        assert.True(t, errors.Is(channelerInstance.Errors[cbName], searchedError), cbName)
     }
 }
\ No newline at end of file
@@ -0,0 +1,126 @@
+package channeler
+
+import (
+    "context"
+    "sync"
+    "time"
+)
+
+/**
+Describes a callback whose dependencies are satisfied and which waits for one of the Channeler.MaxConcurrency slots
+ */
+type ReadyCallback struct {
+    CallbackName string
+    //when its dependencies were satisfied
+    ReadyAt      time.Time
+    //number of callbacks of the chain waiting for this one
+    DependantsNb int
+}
+
+/**
+Tells whether callback a must be given a free slot before callback b. Callbacks considered equal are served in arrival order
+ */
+type ReadyQueueOrder func(a, b *ReadyCallback) bool
+
+/**
+Default order : the callback which has been ready for the longest time goes first
+ */
+func FirstReadyFirst(a, b *ReadyCallback) bool {
+    return a.ReadyAt.Before(b.ReadyAt)
+}
+
+/**
+The callback unlocking the greatest number of other callbacks goes first, then the one which has been ready for the longest time
+ */
+func MostDependantsFirst(a, b *ReadyCallback) bool {
+    if (a.DependantsNb != b.DependantsNb) {
+        return a.DependantsNb > b.DependantsNb
+    }
+    return FirstReadyFirst(a, b)
+}
+
+type slotRequest struct {
+    callback *ReadyCallback
+    granted  chan struct{}
+}
+
+/**
+Hand out a limited number of execution slots to ready callbacks, following a ReadyQueueOrder.
+A nil *concurrencySlots does not limit anything
+ */
+type concurrencySlots struct {
+    mutex   sync.Mutex
+    free    int
+    order   ReadyQueueOrder
+    waiting []*slotRequest
+}
+
+func newConcurrencySlots(maxConcurrency int, order ReadyQueueOrder) *concurrencySlots {
+    if (maxConcurrency <= 0) {
+        return nil
+    }
+    if (order == nil) {
+        order = FirstReadyFirst
+    }
+    return &concurrencySlots{free: maxConcurrency, order: order}
+}
+
+/**
+Block until the callback is given a slot, or until ctx is done in which case ctx.Err() is returned and no slot is held
+ */
+func (slots *concurrencySlots) acquire(ctx context.Context, callback *ReadyCallback) error {
+    if (slots == nil) {
+        return nil
+    }
+    slots.mutex.Lock()
+    if (slots.free > 0 && len(slots.waiting) == 0) {
+        slots.free--
+        slots.mutex.Unlock()
+        return nil
+    }
+    request := &slotRequest{callback, make(chan struct{})}
+    slots.waiting = append(slots.waiting, request)
+    slots.mutex.Unlock()
+
+    select {
+    case <-request.granted:
+        return nil
+    case <-ctx.Done():
+        slots.mutex.Lock()
+        for position, waitingRequest := range slots.waiting {
+            if (waitingRequest == request) {
+                slots.waiting = append(slots.waiting[:position], slots.waiting[position+1:]...)
+                slots.mutex.Unlock()
+                return ctx.Err()
+            }
+        }
+        slots.mutex.Unlock()
+        //the slot was granted in the meantime : hand it over to someone else
+        slots.release()
+        return ctx.Err()
+    }
+}
+
+/**
+Give the slot held by a finished callback to the first waiting callback according to the order, or put it back
+ */
+func (slots *concurrencySlots) release() {
+    if (slots == nil) {
+        return
+    }
+    slots.mutex.Lock()
+    defer slots.mutex.Unlock()
+    if (len(slots.waiting) == 0) {
+        slots.free++
+        return
+    }
+    next := 0
+    for position := 1; position < len(slots.waiting); position++ {
+        if (slots.order(slots.waiting[position].callback, slots.waiting[next].callback)) {
+            next = position
+        }
+    }
+    request := slots.waiting[next]
+    slots.waiting = append(slots.waiting[:next], slots.waiting[next+1:]...)
+    close(request.granted)
+}
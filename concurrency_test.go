@@ -0,0 +1,106 @@
+package channeler
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+    "github.com/stretchr/testify/assert"
+)
+
+/**
+No more than MaxConcurrency callbacks may run at once, while dependencies are still honoured
+ */
+func TestChanneler_MaxConcurrency(t *testing.T) {
+    var running, maxRunning int32
+    var finishedMutex sync.Mutex
+    finished := map[string]bool{}
+    callbackChain := CallbackChain{}
+    for position := 0; position < 8; position++ {
+        callbackName := fmt.Sprintf("node%d", position)
+        var dependenciesNames []string
+        if (position >= 4) {
+            dependenciesNames = []string{fmt.Sprintf("node%d", position - 4)}
+        }
+        callbackChain[callbackName] = NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            nowRunning := atomic.AddInt32(&running, 1)
+            for {
+                observed := atomic.LoadInt32(&maxRunning)
+                if (nowRunning <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, nowRunning)) {
+                    break
+                }
+            }
+            finishedMutex.Lock()
+            for dependencyName := range dependencies {
+                assert.True(t, finished[dependencyName], dependencyName)
+            }
+            finishedMutex.Unlock()
+            time.Sleep(20 * time.Millisecond)
+            finishedMutex.Lock()
+            finished[callbackName] = true
+            finishedMutex.Unlock()
+            atomic.AddInt32(&running, -1)
+            return callbackName, nil
+        }, dependenciesNames)
+    }
+    channelerInstance := NewChanneler(&callbackChain)
+    channelerInstance.MaxConcurrency = 2
+
+    _, err := channelerInstance.Run()
+    assert.Nil(t, err)
+    assert.Equal(t, int32(2), atomic.LoadInt32(&maxRunning))
+    for callbackName := range callbackChain {
+        assert.Equal(t, callbackName, channelerInstance.Results[callbackName])
+    }
+}
+
+/**
+A freed slot goes to the waiting callback picked by the ReadyQueueOrder
+ */
+func TestConcurrencySlots_Order(t *testing.T) {
+    slots := newConcurrencySlots(1, MostDependantsFirst)
+    assert.Nil(t, slots.acquire(context.Background(), &ReadyCallback{"first", time.Now(), 0}))
+
+    readyAt := time.Now()
+    waiting := []*ReadyCallback{
+        {"oldest", readyAt, 1},
+        {"busiest", readyAt.Add(time.Second), 3},
+        {"newest", readyAt.Add(2 * time.Second), 1},
+    }
+    grantedOrder := make(chan string, len(waiting))
+    for position, readyCallback := range waiting {
+        go func(readyCallback *ReadyCallback) {
+            assert.Nil(t, slots.acquire(context.Background(), readyCallback))
+            grantedOrder <- readyCallback.CallbackName
+        }(readyCallback)
+        //wait for the request to be queued so that the arrival order is known
+        assert.Eventually(t, func() bool {
+            slots.mutex.Lock()
+            defer slots.mutex.Unlock()
+            return len(slots.waiting) == position + 1
+        }, time.Second, time.Millisecond)
+    }
+    var served []string
+    for range waiting {
+        slots.release()
+        served = append(served, <-grantedOrder)
+    }
+    assert.Equal(t, []string{"busiest", "oldest", "newest"}, served)
+}
+
+/**
+A callback waiting for a slot when the run is cancelled gives up its place in the queue
+ */
+func TestConcurrencySlots_AcquireCancelled(t *testing.T) {
+    slots := newConcurrencySlots(1, nil)
+    assert.Nil(t, slots.acquire(context.Background(), &ReadyCallback{CallbackName: "holder"}))
+    ctx, cancel := context.WithTimeout(context.Background(), 10 * time.Millisecond)
+    defer cancel()
+    assert.True(t, errors.Is(slots.acquire(ctx, &ReadyCallback{CallbackName: "waiter"}), context.DeadlineExceeded))
+    assert.Empty(t, slots.waiting)
+    slots.release()
+    assert.Equal(t, 1, slots.free)
+}
@@ -0,0 +1,83 @@
+package channeler
+
+import (
+    "context"
+    "errors"
+    "sync/atomic"
+    "testing"
+    "time"
+    "github.com/stretchr/testify/assert"
+)
+
+/**
+Cancelling the context must cancel the in-flight callback through its ctx, never start its dependents
+and make RunContext return promptly even if another callback ignores the context
+ */
+func TestChanneler_RunContextCancellation(t *testing.T) {
+    var dependentStarted int32
+    channelerInstance := NewChanneler(&CallbackChain{
+        "slow": NewContextChanneledCallback(func(ctx context.Context, dependencies CallbackResults) (interface{}, error) {
+            select {
+            case <-time.After(5 * time.Second):
+                return "too late", nil
+            case <-ctx.Done():
+                return nil, ctx.Err()
+            }
+        }, []string{}),
+        "stubborn": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            time.Sleep(5 * time.Second)
+            return "too late", nil
+        }, []string{}),
+        "fast": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return "fast", nil
+        }, []string{}),
+        "dependent": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            atomic.AddInt32(&dependentStarted, 1)
+            return "dependent", nil
+        }, []string{"slow", "fast"}),
+    })
+    ctx, cancel := context.WithCancel(context.Background())
+    time.AfterFunc(50 * time.Millisecond, cancel)
+
+    start := time.Now()
+    _, err := channelerInstance.RunContext(ctx)
+    assert.True(t, errors.Is(err, context.Canceled))
+    assert.Less(t, time.Since(start), time.Second)
+
+    assert.Equal(t, "fast", channelerInstance.Results["fast"])
+    assert.Nil(t, channelerInstance.Errors["fast"])
+    for _, callbackName := range []string{"slow", "stubborn", "dependent"} {
+        var cancelledErr *CancelledError
+        assert.True(t, errors.As(channelerInstance.Errors[callbackName], &cancelledErr), callbackName)
+        assert.Equal(t, callbackName, cancelledErr.CallbackName)
+        assert.True(t, errors.Is(channelerInstance.Errors[callbackName], context.Canceled))
+        assert.Nil(t, channelerInstance.Results[callbackName])
+    }
+    assert.Equal(t, int32(0), atomic.LoadInt32(&dependentStarted))
+}
+
+/**
+A context already done does not start any callback
+ */
+func TestChanneler_RunContextAlreadyCancelled(t *testing.T) {
+    channelerInstance := initFruitsChannelerWithStandardCbChain(t, timeDurationByFruitAndColor{})
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+    _, err := channelerInstance.RunContext(ctx)
+    assert.True(t, errors.Is(err, context.Canceled))
+    for callbackName := range *channelerInstance.CallbackChain {
+        assert.True(t, errors.Is(channelerInstance.Errors[callbackName], context.Canceled), callbackName)
+    }
+}
+
+/**
+The adapter makes old-style callbacks usable where a context-aware one is expected
+ */
+func TestChanneledCallbackCallbackFunction_WithContext(t *testing.T) {
+    callbackFunction := ChanneledCallbackCallbackFunction(func(dependencies CallbackResults) (interface{}, error) {
+        return dependencies["a"], nil
+    })
+    result, err := callbackFunction.WithContext()(context.Background(), CallbackResults{"a": 1})
+    assert.Nil(t, err)
+    assert.Equal(t, 1, result)
+}
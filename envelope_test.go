@@ -0,0 +1,30 @@
+package channeler
+
+import (
+    "errors"
+    "testing"
+    "github.com/stretchr/testify/assert"
+)
+
+/**
+A callback legitimately returning an error value as its result is a success, and its dependents receive that value
+ */
+func TestChanneler_RunErrorValuedResult(t *testing.T) {
+    validationReport := errors.New("field name is mandatory")
+    channelerInstance := NewChanneler(&CallbackChain{
+        "validate": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return validationReport, nil
+        }, []string{}),
+        "render": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return "rendered with : " + dependencies["validate"].(error).Error(), nil
+        }, []string{"validate"}),
+    })
+
+    report, err := channelerInstance.Run()
+    assert.Nil(t, err)
+    assert.True(t, report.Succeeded())
+    assert.Equal(t, validationReport, channelerInstance.Results["validate"])
+    assert.Nil(t, channelerInstance.Errors["validate"])
+    assert.Equal(t, "rendered with : field name is mandatory", channelerInstance.Results["render"])
+    assert.Nil(t, channelerInstance.Errors["render"])
+}
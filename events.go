@@ -0,0 +1,130 @@
+package channeler
+
+import (
+    "sync"
+    "time"
+)
+
+type EventType int
+
+const (
+    //the callback function is invoked for the first time
+    NodeStarted EventType = iota
+    NodeSucceeded
+    //the callback function was invoked but did not succeed : it failed, timed out or was cancelled
+    NodeFailed
+    //the callback function was never invoked : a required dependency failed or the run was stopped first
+    NodeSkipped
+    //the run is over, the event holding its RunReport and error
+    RunFinished
+)
+
+var eventTypeNames = []string{"node started", "node succeeded", "node failed", "node skipped", "run finished"}
+
+func (eventType EventType) String() string {
+    if (eventType < 0 || int(eventType) >= len(eventTypeNames)) {
+        return "unknown"
+    }
+    return eventTypeNames[eventType]
+}
+
+/**
+Sent by Execution.Events() each time a callback starts or is over, and once the run is over
+ */
+type Event struct {
+    Type         EventType
+    //empty for RunFinished
+    CallbackName string
+    At           time.Time
+    //status of the callback once it is over, Running for NodeStarted, unset for RunFinished
+    Status       NodeStatus
+    //result of the callback (which may be a partial result when Err is set), or the *RunReport for RunFinished
+    Value        interface{}
+    //error of the callback, or the error returned by Wait() for RunFinished
+    Err          error
+}
+
+/**
+Event telling that a callback is over
+ */
+func finishedEvent(outcome *NodeOutcome) Event {
+    event := Event{NodeFailed, outcome.CallbackName, outcome.FinishedAt, outcome.Status, outcome.Value, outcome.Err}
+    if (outcome.Status == Succeeded) {
+        event.Type = NodeSucceeded
+    } else if (outcome.StartedAt.IsZero()) {
+        event.Type = NodeSkipped
+    }
+    return event
+}
+
+/**
+Events of a run, replayed to each of its subscribers
+ */
+type eventsLog struct {
+    mutex    sync.Mutex
+    events   []Event
+    //callbacks whose finishedEvent was recorded
+    over     map[string]bool
+    finished bool
+    //closed, then replaced, each time an event is recorded
+    recorded chan struct{}
+}
+
+func newEventsLog() *eventsLog {
+    return &eventsLog{over: map[string]bool{}, recorded: make(chan struct{})}
+}
+
+/**
+Record an event unless the run is already finished, or the callback is already over, and tell whether it was
+ */
+func (events *eventsLog) record(event Event) bool {
+    events.mutex.Lock()
+    defer events.mutex.Unlock()
+    if (events.finished || events.over[event.CallbackName]) {
+        return false
+    }
+    if (event.Type == RunFinished) {
+        events.finished = true
+    } else if (event.Type != NodeStarted) {
+        events.over[event.CallbackName] = true
+    }
+    events.events = append(events.events, event)
+    close(events.recorded)
+    events.recorded = make(chan struct{})
+    return true
+}
+
+/**
+Return a channel receiving every event recorded so far then the upcoming ones, closed after RunFinished.
+Its capacity is the maximum number of events of the run so that nothing blocks when it is not read
+ */
+func (events *eventsLog) subscribe(capacity int) <-chan Event {
+    subscription := make(chan Event, capacity)
+    go func() {
+        sent := 0
+        for {
+            events.mutex.Lock()
+            pending, finished, recorded := events.events[sent:], events.finished, events.recorded
+            events.mutex.Unlock()
+            for _, event := range pending {
+                subscription <- event
+            }
+            sent += len(pending)
+            if (finished) {
+                close(subscription)
+                return
+            }
+            <-recorded
+        }
+    }()
+    return subscription
+}
+
+/**
+Return a channel receiving the events of the run as they happen : NodeStarted when a callback function is invoked,
+NodeSucceeded, NodeFailed or NodeSkipped once a callback is over, and RunFinished last, after which it is closed.
+Every call returns a new channel which first receives the events which already happened
+ */
+func (execution *Execution) Events() <-chan Event {
+    return execution.events.subscribe(2 * len(execution.plan.callbackNames) + 1)
+}
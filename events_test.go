@@ -0,0 +1,92 @@
+package channeler
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "github.com/stretchr/testify/assert"
+)
+
+/**
+Events are streamed as callbacks start and end, RunFinished closing the stream
+ */
+func TestExecution_Events(t *testing.T) {
+    channelerInstance := NewChanneler(&CallbackChain{
+        "getHeader": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return "header", nil
+        }, []string{}),
+        "getBody": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return nil, errTransient
+        }, []string{}),
+        "renderPage": NewChanneledCallback(noopCallback, []string{"getHeader", "getBody"}),
+    })
+    execution, err := channelerInstance.Start(context.Background())
+    assert.Nil(t, err)
+
+    var received []Event
+    for event := range execution.Events() {
+        received = append(received, event)
+    }
+    byCallback := map[string][]EventType{}
+    for _, event := range received {
+        if (event.Type != RunFinished) {
+            byCallback[event.CallbackName] = append(byCallback[event.CallbackName], event.Type)
+        }
+    }
+    assert.Equal(t, []EventType{NodeStarted, NodeSucceeded}, byCallback["getHeader"])
+    assert.Equal(t, []EventType{NodeStarted, NodeFailed}, byCallback["getBody"])
+    assert.Equal(t, []EventType{NodeSkipped}, byCallback["renderPage"])
+
+    lastEvent := received[len(received) - 1]
+    assert.Equal(t, RunFinished, lastEvent.Type)
+    report, runErr := execution.Wait()
+    assert.Equal(t, report, lastEvent.Value)
+    assert.Equal(t, runErr, lastEvent.Err)
+    for _, event := range received {
+        switch event.CallbackName {
+        case "getHeader":
+            if (event.Type == NodeSucceeded) {
+                assert.Equal(t, "header", event.Value)
+                assert.Equal(t, Succeeded, event.Status)
+            }
+        case "renderPage":
+            assert.Equal(t, UpstreamFailed, event.Status)
+            assert.True(t, errors.Is(event.Err, errTransient))
+        }
+    }
+
+    //late subscribers get the whole history
+    var replayed []Event
+    for event := range execution.Events() {
+        replayed = append(replayed, event)
+    }
+    assert.Equal(t, received, replayed)
+}
+
+/**
+Callbacks still running when the run is cancelled are reported as failed before RunFinished
+ */
+func TestExecution_EventsCancelled(t *testing.T) {
+    stopped := make(chan struct{})
+    channelerInstance := NewChanneler(&CallbackChain{
+        "stubborn": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            <-stopped
+            return "too late", nil
+        }, []string{}),
+        "dependent": NewChanneledCallback(noopCallback, []string{"stubborn"}),
+    })
+    defer close(stopped)
+    execution, err := channelerInstance.Start(context.Background())
+    assert.Nil(t, err)
+
+    var types []EventType
+    for event := range execution.Events() {
+        types = append(types, event.Type)
+        if (event.Type == NodeStarted) {
+            execution.Cancel()
+        }
+    }
+    assert.Equal(t, NodeStarted, types[0])
+    assert.ElementsMatch(t, []EventType{NodeFailed, NodeSkipped}, types[1:3])
+    assert.Equal(t, []EventType{RunFinished}, types[3:])
+}
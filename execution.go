@@ -0,0 +1,379 @@
+package channeler
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "log/slog"
+    "runtime/debug"
+    "time"
+)
+
+/**
+Returned by Await() for a name which is not a callback of the plan
+ */
+type UnknownCallbackError struct {
+    CallbackName string
+}
+func(err *UnknownCallbackError) Error() string {
+    return fmt.Sprintf("%s is not a callback of the plan", err.CallbackName)
+}
+
+/**
+Handle on a run started by Start() : the run goes on in the background while the results of its callbacks
+can be awaited one by one, and the whole run can be waited for or cancelled
+ */
+type Execution struct {
+    plan         *Plan
+    runID        string
+    logger       *slog.Logger
+    state        *runState
+    startedAt    time.Time
+    //closed once the callback of the same name is over, by callback name
+    callbacksDone map[string]chan struct{}
+    //closed once the OnFinish hook and the span of the callback of the same name are done with, by callback name
+    callbacksFinished map[string]chan struct{}
+    cancel       context.CancelCauseFunc
+    span         Span
+    //spans of the callbacks, by callback name
+    spans        map[string]Span
+    events       *eventsLog
+    //closed once report and err are set
+    done         chan struct{}
+    report       *RunReport
+    err          error
+}
+
+/**
+Same as Run(), but without waiting for the callbacks to be over : the returned Execution tells when they are.
+The Results, Errors and AttemptsErrors of the Channeler are left untouched, the results being read from the Execution
+ */
+func (channeler *Channeler) Start(ctx context.Context) (*Execution, error) {
+    plan, err := channeler.Compile()
+    if (err != nil) {
+        return nil, err
+    }
+    return plan.Start(ctx), nil
+}
+
+/**
+Launch all callbacks simultaneously (1 goroutine per callback of the plan) and block them according to their dependencies
+using their channels, without waiting for them to be over
+ */
+func (plan *Plan) Start(ctx context.Context) *Execution {
+    execution := &Execution{
+        plan: plan,
+        runID: newRunID(),
+        startedAt: time.Now(),
+        callbacksDone: map[string]chan struct{}{},
+        callbacksFinished: map[string]chan struct{}{},
+        spans: map[string]Span{},
+        done: make(chan struct{}),
+        events: newEventsLog(),
+    }
+    execution.logger = plan.logger
+    if (execution.logger == nil) {
+        execution.logger = slog.New(discardHandler{})
+    }
+    execution.logger = execution.logger.With("run_id", execution.runID)
+    execution.logger.Info("run started", "callbacks", len(plan.callbackNames))
+    execution.state = plan.newRunState(execution.logger)
+    tracer := plan.tracer
+    if (tracer == nil) {
+        tracer = noopTracer{}
+    }
+    ctx, execution.span = tracer.Start(ctx, "channeler.run", Attribute{"channeler.run_id", execution.runID}, Attribute{"channeler.callbacks", len(plan.callbackNames)})
+    cancelTimeout := context.CancelFunc(func() {})
+    if (plan.timeout > 0) {
+        ctx, cancelTimeout = context.WithTimeout(ctx, plan.timeout)
+    }
+    ctx, execution.cancel = context.WithCancelCause(ctx)
+    slots := newConcurrencySlots(plan.maxConcurrency, plan.readyQueueOrder)
+    //the context of each callback carries its span, so that the spans started by its callback function are its children
+    callbacksContexts := map[string]context.Context{}
+    for _, callbackName := range plan.callbackNames {
+        execution.callbacksDone[callbackName] = make(chan struct{})
+        execution.callbacksFinished[callbackName] = make(chan struct{})
+        callbacksContexts[callbackName], execution.spans[callbackName] = tracer.Start(ctx, callbackName, Attribute{"channeler.callback", callbackName})
+    }
+    for callbackName, node := range plan.nodes {
+        go execution.runCallback(callbacksContexts[callbackName], callbackName, node, slots)
+    }
+    go func() {
+        defer cancelTimeout()
+        defer execution.cancel(nil)
+        execution.collect(ctx)
+    }()
+    return execution
+}
+
+/**
+Wait for the dependencies of a callback, invoke it and inform its dependants and the collector that it is over
+ */
+func (execution *Execution) runCallback(ctx context.Context, callbackName string, node *planNode, slots *concurrencySlots) {
+    channeledCallback := node.channeledCallback
+    outcomes := execution.state.outcomes
+    logger := execution.logger.With("callback", callbackName)
+    var err error
+    var result interface{}
+    var attemptsErrors []error
+    status := Succeeded
+    //whether err comes from the run being cancelled or timed out rather than from the callback chain
+    interrupted := false
+    //if there are blocking dependencies wait for them to be fetched through the callback's inbox, reacting to the first
+    //required one which fails instead of waiting for the others...
+    if (len(node.dependencies) > 0) {
+        logger.Debug("waiting for dependencies", "dependencies", node.dependencies)
+    }
+    dependenciesResults, failedDependencies := channeledCallback.awaitDependencies(ctx, execution.state.inboxes[callbackName])
+    //whenever an error is received from a required dependency, we do not invoke the channeledCallback.CallbackFunction
+    //as the dependencies could not be fullfilled.
+    if (len(failedDependencies) > 0) {
+        err, status = upstreamFailure(callbackName, failedDependencies), UpstreamFailed
+    }
+    //a cancelled or timed out run does not start callbacks anymore : each pending one is marked on its own
+    if (ctx.Err() != nil) {
+        ready := err == nil && len(dependenciesResults) == len(node.dependencies)
+        err, status, interrupted = interruption(ctx, callbackName, execution.startedAt), interruptedStatus(ctx, ready), true
+    }
+    //...then wait for a free slot if the concurrency is limited...
+    if (err == nil) {
+        readyCallback := &ReadyCallback{callbackName, time.Now(), len(node.dependants)}
+        outcomes.update(callbackName, func(outcome *NodeOutcome) {
+            outcome.ReadyAt = readyCallback.ReadyAt
+        })
+        if (slots != nil) {
+            logger.Debug("waiting for a free slot")
+        }
+        if slotErr := slots.acquire(ctx, readyCallback); slotErr != nil {
+            err, status, interrupted = interruption(ctx, callbackName, execution.startedAt), interruptedStatus(ctx, true), true
+        }
+    }
+    startedAt := time.Now()
+    //the run may have been stopped and reported meanwhile, the callback being told as not started
+    if (err == nil && !outcomes.update(callbackName, func(outcome *NodeOutcome) {
+        outcome.Status, outcome.StartedAt = Running, startedAt
+    })) {
+        slots.release()
+        err, status, interrupted = interruption(ctx, callbackName, execution.startedAt), interruptedStatus(ctx, true), true
+    }
+    if(err == nil) {
+        execution.events.record(Event{Type: NodeStarted, CallbackName: callbackName, At: startedAt, Status: Running})
+        logger.Info("callback started")
+        if (execution.plan.hooks.OnStart != nil) {
+            execution.callHook(logger, "OnStart", func() {
+                execution.plan.hooks.OnStart(ctx, callbackName)
+            })
+        }
+        //...then call the CallbackFunction along with the args from dependencies if any...
+        result, attemptsErrors, err = channeledCallback.invoke(ctx, callbackName, dependenciesResults, execution.plan.interceptors, execution.plan.crashOnPanic)
+        slots.release()
+        var timeoutErr *TimeoutError
+        if (err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err())) {
+            err, status, interrupted = interruption(ctx, callbackName, execution.startedAt), interruptedStatus(ctx, true), true
+        } else if (errors.As(err, &timeoutErr) && channeledCallback.timedOut(callbackName, timeoutErr)) {
+            status = TimedOut
+        } else if (err != nil) {
+            status = Failed
+        }
+    }
+    var finished NodeOutcome
+    //once the run is over, its report tells how the callback ended
+    updated := outcomes.update(callbackName, func(outcome *NodeOutcome) {
+        outcome.Status, outcome.FinishedAt = status, time.Now()
+        outcome.FailedDependencies = failedDependenciesNames(failedDependencies)
+        outcome.AttemptsErrors, outcome.Attempts = attemptsErrors, len(attemptsErrors)
+        //a failed callback may still have returned a partial result
+        outcome.Value, outcome.Err = result, err
+        if (err == nil) {
+            outcome.Attempts++
+        }
+        finished = *outcome
+    })
+    if (updated) {
+        execution.finish(&finished)
+        logOutcome(logger, &finished)
+    }
+    close(execution.callbacksDone[callbackName])
+
+    //stop the whole run before dependent callbacks learn about the failure so that they get cancelled as well
+    if (err != nil && execution.plan.errorPolicy == FailFast && !interrupted) {
+        execution.cancel(&FailFastError{callbackName, err})
+    }
+    if (err != nil && len(node.dependants) > 0) {
+        logger.Debug("propagating failure to dependants", "dependants", node.dependants, "error", err)
+    }
+    execution.state.feeds[callbackName].propagate(envelope{callbackName, result, err})
+    //the result channel is written last : once every result is received, the channels can be closed
+    execution.state.results[callbackName] <- envelope{callbackName, result, err}
+}
+
+/**
+Wait for all results or errors to be gathered, or for ctx to be done, then build the report of the run
+ */
+func (execution *Execution) collect(ctx context.Context) {
+    var runErr error
+    collect:
+    for _, callbackChannel := range execution.state.results {
+        select {
+        case <-callbackChannel:
+        case <-ctx.Done():
+            //do not wait for callbacks which are still running : they can only write into buffered channels
+            //that are left open and garbage collected once they are done
+            runErr = context.Cause(ctx)
+            break collect
+        }
+    }
+    //every result was received : no callback can write into the channels anymore
+    if (runErr == nil) {
+        execution.state.closeAllChannels(execution.logger)
+    }
+    execution.report = &RunReport{execution.startedAt, time.Now(), execution.state.outcomes.snapshot(ctx, execution.startedAt)}
+    //every callback may be over before the collector notices that the run was stopped, e.g. by a failure
+    if (runErr == nil && ctx.Err() != nil && !execution.report.Succeeded()) {
+        runErr = context.Cause(ctx)
+    }
+    execution.err = newRunError(execution.report, runErr)
+    //callbacks which were still running are over as far as the run is concerned
+    for _, callbackName := range execution.plan.callbackNames {
+        execution.finish(execution.report.Outcomes[callbackName])
+    }
+    //a callback may have been telling it is over on its own when the run was stopped
+    for _, callbackName := range execution.plan.callbackNames {
+        <-execution.callbacksFinished[callbackName]
+    }
+    execution.events.record(Event{Type: RunFinished, At: execution.report.FinishedAt, Value: execution.report, Err: execution.err})
+    execution.span.SetAttributes(Attribute{"channeler.succeeded", execution.report.Succeeded()})
+    if (execution.err != nil) {
+        execution.span.RecordError(execution.err)
+    }
+    execution.span.End()
+    if (execution.err != nil) {
+        execution.logger.Warn("run finished", "duration", execution.report.Duration(), "error", execution.err)
+    } else {
+        execution.logger.Info("run finished", "duration", execution.report.Duration())
+    }
+    close(execution.done)
+}
+
+/**
+Return the identifier of the run, also given to the records of the Channeler's Logger
+ */
+func (execution *Execution) RunID() string {
+    return execution.runID
+}
+
+/**
+slog.Handler dropping every record, used when the Channeler has no Logger
+ */
+type discardHandler struct{}
+
+func (handler discardHandler) Enabled(ctx context.Context, level slog.Level) bool { return false }
+func (handler discardHandler) Handle(ctx context.Context, record slog.Record) error { return nil }
+func (handler discardHandler) WithAttrs(attrs []slog.Attr) slog.Handler { return handler }
+func (handler discardHandler) WithGroup(name string) slog.Handler { return handler }
+
+/**
+Return a random identifier for a run
+ */
+func newRunID() string {
+    randomBytes := make([]byte, 8)
+    rand.Read(randomBytes)
+    return hex.EncodeToString(randomBytes)
+}
+
+/**
+Log how a callback ended : failures are warnings
+ */
+func logOutcome(logger *slog.Logger, outcome *NodeOutcome) {
+    if (outcome.Status == Succeeded) {
+        logger.Info("callback succeeded", "duration", outcome.Duration(), "attempts", outcome.Attempts)
+        return
+    }
+    if (outcome.Status == UpstreamFailed) {
+        logger.Warn("callback not invoked because dependencies failed", "failed_dependencies", outcome.FailedDependencies, "error", outcome.Err)
+        return
+    }
+    logger.Warn("callback did not succeed", "status", outcome.Status.String(), "error", outcome.Err, "attempts", outcome.Attempts)
+}
+
+/**
+Tell the subscribers to the events and the OnFinish hook that a callback is over and end its span, unless they already know
+ */
+func (execution *Execution) finish(outcome *NodeOutcome) {
+    if (!execution.events.record(finishedEvent(outcome))) {
+        return
+    }
+    defer close(execution.callbacksFinished[outcome.CallbackName])
+    span := execution.spans[outcome.CallbackName]
+    span.SetAttributes(outcomeAttributes(outcome, execution.startedAt)...)
+    if (outcome.Err != nil) {
+        span.RecordError(outcome.Err)
+    }
+    span.End()
+    if (execution.plan.hooks.OnFinish != nil) {
+        execution.callHook(execution.logger.With("callback", outcome.CallbackName), "OnFinish", func() {
+            execution.plan.hooks.OnFinish(*outcome)
+        })
+    }
+}
+
+/**
+Call a hook, a panic being logged instead of crashing the process unless the plan has to crash on panics
+ */
+func (execution *Execution) callHook(logger *slog.Logger, hookName string, hook func()) {
+    if (!execution.plan.crashOnPanic) {
+        defer func() {
+            if recovered := recover(); recovered != nil {
+                logger.Error("hook panicked", "hook", hookName, "panic", recovered, "stack", string(debug.Stack()))
+            }
+        }()
+    }
+    hook()
+}
+
+/**
+Block until the run is over and return what Run() would have returned
+ */
+func (execution *Execution) Wait() (*RunReport, error) {
+    <-execution.done
+    return execution.report, execution.err
+}
+
+/**
+Closed once the run is over, i.e. once Wait() does not block anymore
+ */
+func (execution *Execution) Done() <-chan struct{} {
+    return execution.done
+}
+
+/**
+Cancel the run as if its context was cancelled
+ */
+func (execution *Execution) Cancel() {
+    execution.cancel(nil)
+}
+
+/**
+Block until the given callback is over, or until the run is, and return its result and error.
+A failed callback may have returned a partial result along with its error
+ */
+func (execution *Execution) Await(callbackName string) (interface{}, error) {
+    callbackDone, isset := execution.callbacksDone[callbackName]
+    if (!isset) {
+        return nil, &UnknownCallbackError{callbackName}
+    }
+    select {
+    case <-callbackDone:
+        //the callback may have ended after the run, whose report then tells how it ended
+        if outcome := execution.state.outcomes.get(callbackName); outcome.Status.IsFinal() {
+            return outcome.Value, outcome.Err
+        }
+        <-execution.done
+    case <-execution.done:
+    }
+    outcome := execution.report.Outcomes[callbackName]
+    return outcome.Value, outcome.Err
+}
@@ -0,0 +1,78 @@
+package channeler
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "time"
+    "github.com/stretchr/testify/assert"
+)
+
+/**
+The result of an early callback can be consumed while the rest of the chain keeps running
+ */
+func TestChanneler_StartAwait(t *testing.T) {
+    releaseBody := make(chan struct{})
+    channelerInstance := NewChanneler(&CallbackChain{
+        "getHeader": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return "header", nil
+        }, []string{}),
+        "getBody": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            <-releaseBody
+            return "body", nil
+        }, []string{}),
+        "renderPage": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return dependencies["getHeader"].(string) + " " + dependencies["getBody"].(string), nil
+        }, []string{"getHeader", "getBody"}),
+    })
+    execution, err := channelerInstance.Start(context.Background())
+    assert.Nil(t, err)
+
+    header, err := execution.Await("getHeader")
+    assert.Nil(t, err)
+    assert.Equal(t, "header", header)
+    select {
+    case <-execution.Done():
+        t.Fatal("the run must not be over before getBody returns")
+    default:
+    }
+
+    close(releaseBody)
+    page, err := execution.Await("renderPage")
+    assert.Nil(t, err)
+    assert.Equal(t, "header body", page)
+    <-execution.Done()
+    report, err := execution.Wait()
+    assert.Nil(t, err)
+    assert.True(t, report.Succeeded())
+    //the Channeler's own results are only filled by Run()
+    assert.Nil(t, channelerInstance.Results)
+
+    _, err = execution.Await("missing")
+    var unknownErr *UnknownCallbackError
+    assert.True(t, errors.As(err, &unknownErr))
+}
+
+/**
+Cancelling an execution cancels the callbacks still running and does not start the pending ones
+ */
+func TestExecution_Cancel(t *testing.T) {
+    channelerInstance := NewChanneler(&CallbackChain{
+        "slow": NewContextChanneledCallback(func(ctx context.Context, dependencies CallbackResults) (interface{}, error) {
+            <-ctx.Done()
+            return nil, ctx.Err()
+        }, []string{}),
+        "dependent": NewChanneledCallback(noopCallback, []string{"slow"}),
+    })
+    plan, err := channelerInstance.Compile()
+    assert.Nil(t, err)
+    execution := plan.Start(context.Background())
+    time.AfterFunc(10 * time.Millisecond, execution.Cancel)
+
+    _, err = execution.Await("dependent")
+    assert.True(t, errors.Is(err, context.Canceled))
+    report, err := execution.Wait()
+    assert.True(t, errors.Is(err, context.Canceled))
+    assert.Equal(t, Cancelled, report.Outcomes["slow"].Status)
+    assert.Equal(t, Skipped, report.Outcomes["dependent"].Status)
+}
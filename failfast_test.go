@@ -0,0 +1,79 @@
+package channeler
+
+import (
+    "context"
+    "errors"
+    "sync/atomic"
+    "testing"
+    "time"
+    "github.com/stretchr/testify/assert"
+)
+
+/**
+With the FailFast policy, the first failure cancels running callbacks and prevents pending ones from starting
+ */
+func TestChanneler_RunFailFast(t *testing.T) {
+    var startedAfterFailure int32
+    neverStarted := func(dependencies CallbackResults) (interface{}, error) {
+        atomic.AddInt32(&startedAfterFailure, 1)
+        return nil, nil
+    }
+    channelerInstance := NewChanneler(&CallbackChain{
+        "failing": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            time.Sleep(20 * time.Millisecond)
+            return nil, errTransient
+        }, []string{}),
+        "fast": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return "fast", nil
+        }, []string{}),
+        "slow": NewContextChanneledCallback(func(ctx context.Context, dependencies CallbackResults) (interface{}, error) {
+            select {
+            case <-time.After(5 * time.Second):
+                return "too late", nil
+            case <-ctx.Done():
+                return nil, ctx.Err()
+            }
+        }, []string{}),
+        "dependent": NewChanneledCallback(neverStarted, []string{"failing"}),
+        "pending": NewChanneledCallback(neverStarted, []string{"slow"}),
+    })
+    channelerInstance.ErrorPolicy = FailFast
+
+    start := time.Now()
+    _, err := channelerInstance.Run()
+    assert.Less(t, time.Since(start), time.Second)
+    var failFastErr *FailFastError
+    assert.True(t, errors.As(err, &failFastErr))
+    assert.Equal(t, "failing", failFastErr.CallbackName)
+    assert.True(t, errors.Is(err, errTransient))
+
+    assert.Equal(t, errTransient, channelerInstance.Errors["failing"])
+    assert.Equal(t, "fast", channelerInstance.Results["fast"])
+    for _, callbackName := range []string{"slow", "dependent", "pending"} {
+        var cancelledErr *CancelledError
+        assert.True(t, errors.As(channelerInstance.Errors[callbackName], &cancelledErr), callbackName)
+        assert.Equal(t, callbackName, cancelledErr.CallbackName)
+        assert.True(t, errors.As(cancelledErr.Cause, &failFastErr))
+    }
+    time.Sleep(20 * time.Millisecond)
+    assert.Equal(t, int32(0), atomic.LoadInt32(&startedAfterFailure))
+}
+
+/**
+The default ContinueOnError policy lets independent branches run to completion
+ */
+func TestChanneler_RunContinueOnError(t *testing.T) {
+    channelerInstance := NewChanneler(&CallbackChain{
+        "failing": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return nil, errTransient
+        }, []string{}),
+        "slow": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            time.Sleep(20 * time.Millisecond)
+            return "slow", nil
+        }, []string{}),
+    })
+    _, err := channelerInstance.Run()
+    assert.True(t, errors.Is(err, errTransient))
+    assert.Equal(t, "slow", channelerInstance.Results["slow"])
+    assert.Equal(t, errTransient, channelerInstance.Errors["failing"])
+}
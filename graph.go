@@ -0,0 +1,205 @@
+package channeler
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "strings"
+)
+
+//fill color of the callbacks of a graph by status, callbacks with other statuses being left blank
+var statusColors = map[NodeStatus]string{
+    Succeeded: "#b7e1a1",
+    Failed: "#f4a6a6",
+    TimedOut: "#f4a6a6",
+    UpstreamFailed: "#f9d29d",
+    Skipped: "#d9d9d9",
+    Cancelled: "#d9d9d9",
+}
+
+/**
+A callback of a Graph, along with how it ended when the graph comes with a report
+ */
+type GraphNode struct {
+    Name        string  `json:"name"`
+    Status      string  `json:"status,omitempty"`
+    DurationMs  float64 `json:"duration_ms,omitempty"`
+    Error       string  `json:"error,omitempty"`
+}
+
+/**
+A dependency of a Graph, going from the dependency to the callback depending on it
+ */
+type GraphEdge struct {
+    From          string `json:"from"`
+    To            string `json:"to"`
+    Optional      bool   `json:"optional,omitempty"`
+    AcceptPartial bool   `json:"accept_partial,omitempty"`
+}
+
+/**
+Dependency graph of a Plan, which can be written as Graphviz DOT, Mermaid flowchart or JSON
+ */
+type Graph struct {
+    Nodes    []GraphNode            `json:"nodes"`
+    Edges    []GraphEdge            `json:"edges"`
+    Metadata map[string]interface{} `json:"metadata"`
+}
+
+/**
+Return the dependency graph of the plan, nodes and edges being sorted by name. When report is not nil,
+each node tells how its callback ended during that run and is colored accordingly once written
+ */
+func (plan *Plan) Graph(report *RunReport) *Graph {
+    graph := &Graph{Nodes: []GraphNode{}, Edges: []GraphEdge{}, Metadata: map[string]interface{}{
+        "callbacks": len(plan.callbackNames),
+        "max_concurrency": plan.maxConcurrency,
+        "timeout_ms": milliseconds(plan.timeout),
+        "fail_fast": plan.errorPolicy == FailFast,
+    }}
+    if (report != nil) {
+        graph.Metadata["started_at"] = report.StartedAt
+        graph.Metadata["duration_ms"] = milliseconds(report.Duration())
+        graph.Metadata["succeeded"] = report.Succeeded()
+    }
+    for _, callbackName := range plan.callbackNames {
+        node := GraphNode{Name: callbackName}
+        if outcome, isset := report.outcome(callbackName); isset {
+            node.Status, node.DurationMs = outcome.Status.String(), milliseconds(outcome.Duration())
+            if (outcome.Err != nil) {
+                node.Error = outcome.Err.Error()
+            }
+        }
+        graph.Nodes = append(graph.Nodes, node)
+        channeledCallback := plan.nodes[callbackName].channeledCallback
+        for _, dependencyName := range plan.nodes[callbackName].dependencies {
+            dependencyOptions := channeledCallback.DependenciesOptions[dependencyName]
+            graph.Edges = append(graph.Edges, GraphEdge{dependencyName, callbackName, dependencyOptions.Optional, dependencyOptions.AcceptPartial})
+        }
+    }
+    return graph
+}
+
+/**
+Return the outcome of a callback, if report is not nil and has one
+ */
+func (report *RunReport) outcome(callbackName string) (*NodeOutcome, bool) {
+    if (report == nil) {
+        return nil, false
+    }
+    outcome, isset := report.Outcomes[callbackName]
+    return outcome, isset
+}
+
+/**
+Label of a node : its name, followed by its status if any
+ */
+func (node *GraphNode) label(separator string) string {
+    if (node.Status == "") {
+        return node.Name
+    }
+    return node.Name + separator + node.Status
+}
+
+/**
+Fill color of a node according to its status, empty if none
+ */
+func (node *GraphNode) color() string {
+    for status, color := range statusColors {
+        if (status.String() == node.Status) {
+            return color
+        }
+    }
+    return ""
+}
+
+/**
+Label of an edge telling how the dependency is handled when it fails, empty for a required dependency
+ */
+func (edge *GraphEdge) label() string {
+    var options []string
+    if (edge.Optional) {
+        options = append(options, "optional")
+    }
+    if (edge.AcceptPartial) {
+        options = append(options, "accepts partial")
+    }
+    return strings.Join(options, ", ")
+}
+
+/**
+Write the graph in the Graphviz DOT language, non required dependencies being dashed
+ */
+func (graph *Graph) WriteDOT(writer io.Writer) error {
+    quote := func(text string) string {
+        return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(text) + `"`
+    }
+    var builder strings.Builder
+    builder.WriteString("digraph channeler {\n    rankdir=LR;\n    node [shape=box, style=rounded];\n")
+    for _, node := range graph.Nodes {
+        attributes := "label=" + quote(node.label("\n"))
+        if color := node.color(); color != "" {
+            attributes += `, style="rounded,filled", fillcolor=` + quote(color)
+        }
+        fmt.Fprintf(&builder, "    %s [%s];\n", quote(node.Name), attributes)
+    }
+    for _, edge := range graph.Edges {
+        attributes := ""
+        if label := edge.label(); label != "" {
+            attributes = " [style=dashed, label=" + quote(label) + "]"
+        }
+        fmt.Fprintf(&builder, "    %s -> %s%s;\n", quote(edge.From), quote(edge.To), attributes)
+    }
+    builder.WriteString("}\n")
+    _, err := io.WriteString(writer, builder.String())
+    return err
+}
+
+/**
+Write the graph as a Mermaid flowchart, non required dependencies being dotted. Nodes are identified by their position
+so that callback names do not have to be valid Mermaid identifiers
+ */
+func (graph *Graph) WriteMermaid(writer io.Writer) error {
+    quote := func(text string) string {
+        return `"` + strings.ReplaceAll(text, `"`, "#quot;") + `"`
+    }
+    identifiers := map[string]string{}
+    var builder strings.Builder
+    builder.WriteString("flowchart LR\n")
+    for position, node := range graph.Nodes {
+        identifiers[node.Name] = fmt.Sprintf("n%d", position)
+        fmt.Fprintf(&builder, "    %s[%s]\n", identifiers[node.Name], quote(node.label("<br/>")))
+    }
+    for _, edge := range graph.Edges {
+        if label := edge.label(); label != "" {
+            fmt.Fprintf(&builder, "    %s -.->|%s| %s\n", identifiers[edge.From], quote(label), identifiers[edge.To])
+        } else {
+            fmt.Fprintf(&builder, "    %s --> %s\n", identifiers[edge.From], identifiers[edge.To])
+        }
+    }
+    //one class per status, declared in the order of the nodes using it
+    classes := map[string]bool{}
+    for _, node := range graph.Nodes {
+        color := node.color()
+        if (color == "") {
+            continue
+        }
+        class := strings.ReplaceAll(node.Status, " ", "_")
+        if (!classes[class]) {
+            classes[class] = true
+            fmt.Fprintf(&builder, "    classDef %s fill:%s\n", class, color)
+        }
+        fmt.Fprintf(&builder, "    class %s %s\n", identifiers[node.Name], class)
+    }
+    _, err := io.WriteString(writer, builder.String())
+    return err
+}
+
+/**
+Write the graph as an indented JSON document holding its nodes, edges and metadata
+ */
+func (graph *Graph) WriteJSON(writer io.Writer) error {
+    encoder := json.NewEncoder(writer)
+    encoder.SetIndent("", "    ")
+    return encoder.Encode(graph)
+}
@@ -0,0 +1,91 @@
+package channeler
+
+import (
+    "bytes"
+    "encoding/json"
+    "testing"
+    "github.com/stretchr/testify/assert"
+)
+
+func breakfastChanneler() *Channeler {
+    return NewChanneler(&CallbackChain{
+        "getBread": NewChanneledCallback(noopCallback, []string{}),
+        "getJam": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return nil, errTransient
+        }, []string{}),
+        "getButter": NewChanneledCallback(noopCallback, []string{}),
+        "makeToast": NewChanneledCallback(noopCallback, []string{"getBread", "getButter", "getJam"}).WithOptionalDependencies("getButter"),
+    })
+}
+
+func TestPlan_GraphDOT(t *testing.T) {
+    plan, err := breakfastChanneler().Compile()
+    assert.Nil(t, err)
+    var buffer bytes.Buffer
+    assert.Nil(t, plan.Graph(nil).WriteDOT(&buffer))
+    assert.Equal(t, `digraph channeler {
+    rankdir=LR;
+    node [shape=box, style=rounded];
+    "getBread" [label="getBread"];
+    "getButter" [label="getButter"];
+    "getJam" [label="getJam"];
+    "makeToast" [label="makeToast"];
+    "getBread" -> "makeToast";
+    "getButter" -> "makeToast" [style=dashed, label="optional"];
+    "getJam" -> "makeToast";
+}
+`, buffer.String())
+
+    report, _ := plan.Run()
+    buffer.Reset()
+    assert.Nil(t, plan.Graph(report).WriteDOT(&buffer))
+    assert.Contains(t, buffer.String(), `"getJam" [label="getJam\nfailed", style="rounded,filled", fillcolor="#f4a6a6"];`)
+    assert.Contains(t, buffer.String(), `"makeToast" [label="makeToast\nupstream failed", style="rounded,filled", fillcolor="#f9d29d"];`)
+}
+
+func TestPlan_GraphMermaid(t *testing.T) {
+    plan, err := breakfastChanneler().Compile()
+    assert.Nil(t, err)
+    report, _ := plan.Run()
+    var buffer bytes.Buffer
+    assert.Nil(t, plan.Graph(report).WriteMermaid(&buffer))
+    assert.Equal(t, `flowchart LR
+    n0["getBread<br/>succeeded"]
+    n1["getButter<br/>succeeded"]
+    n2["getJam<br/>failed"]
+    n3["makeToast<br/>upstream failed"]
+    n0 --> n3
+    n1 -.->|"optional"| n3
+    n2 --> n3
+    classDef succeeded fill:#b7e1a1
+    class n0 succeeded
+    class n1 succeeded
+    classDef failed fill:#f4a6a6
+    class n2 failed
+    classDef upstream_failed fill:#f9d29d
+    class n3 upstream_failed
+`, buffer.String())
+}
+
+func TestPlan_GraphJSON(t *testing.T) {
+    channelerInstance := breakfastChanneler()
+    channelerInstance.MaxConcurrency = 2
+    plan, err := channelerInstance.Compile()
+    assert.Nil(t, err)
+    report, _ := plan.Run()
+    var buffer bytes.Buffer
+    assert.Nil(t, plan.Graph(report).WriteJSON(&buffer))
+
+    var graph Graph
+    assert.Nil(t, json.Unmarshal(buffer.Bytes(), &graph))
+    assert.Len(t, graph.Nodes, 4)
+    assert.Equal(t, GraphNode{Name: "getJam", Status: "failed", DurationMs: graph.Nodes[2].DurationMs, Error: errTransient.Error()}, graph.Nodes[2])
+    assert.Equal(t, []GraphEdge{
+        {From: "getBread", To: "makeToast"},
+        {From: "getButter", To: "makeToast", Optional: true},
+        {From: "getJam", To: "makeToast"},
+    }, graph.Edges)
+    assert.Equal(t, 4.0, graph.Metadata["callbacks"])
+    assert.Equal(t, 2.0, graph.Metadata["max_concurrency"])
+    assert.Equal(t, false, graph.Metadata["succeeded"])
+}
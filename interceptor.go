@@ -0,0 +1,38 @@
+package channeler
+
+import (
+    "context"
+)
+
+/**
+Invoke the callback function of the given callback, as many times as its RetryPolicy allows
+ */
+type Invoker func(ctx context.Context, callbackName string, dependencies CallbackResults) (interface{}, error)
+
+/**
+Wrap the invocation of callback functions, e.g. to log or measure them, enrich their context or retry them :
+an interceptor is given the next Invoker of the chain and returns the Invoker to call instead
+ */
+type Interceptor func(next Invoker) Invoker
+
+/**
+Functions called around each callback of a run, synchronously from the callback's goroutine, or from the run's one
+for the callbacks still running when the run is stopped. The run is only over once every OnFinish call returned.
+Their panics are recovered and logged at Error level, unless Channeler.CrashOnPanic is set
+ */
+type Hooks struct {
+    //called right before the callback function is invoked, with the context it is invoked with
+    OnStart  func(ctx context.Context, callbackName string)
+    //called once the callback is over, whether its function was invoked or not, with a copy of its outcome
+    OnFinish func(outcome NodeOutcome)
+}
+
+/**
+Chain interceptors around invoker, the first interceptor being the outermost one
+ */
+func intercept(invoker Invoker, interceptors ...Interceptor) Invoker {
+    for position := len(interceptors) - 1; position >= 0; position-- {
+        invoker = interceptors[position](invoker)
+    }
+    return invoker
+}
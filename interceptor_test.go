@@ -0,0 +1,166 @@
+package channeler
+
+import (
+    "context"
+    "errors"
+    "sort"
+    "sync"
+    "testing"
+    "time"
+    "github.com/stretchr/testify/assert"
+)
+
+type userKey struct{}
+
+/**
+The Channeler's interceptors wrap the callback's own ones, and can alter the context given to the callback function
+ */
+func TestChanneler_RunInterceptors(t *testing.T) {
+    var tracesMutex sync.Mutex
+    var traces []string
+    tracing := func(label string) Interceptor {
+        return func(next Invoker) Invoker {
+            return func(ctx context.Context, callbackName string, dependencies CallbackResults) (interface{}, error) {
+                tracesMutex.Lock()
+                traces = append(traces, label + " before " + callbackName)
+                tracesMutex.Unlock()
+                result, err := next(ctx, callbackName, dependencies)
+                tracesMutex.Lock()
+                traces = append(traces, label + " after " + callbackName)
+                tracesMutex.Unlock()
+                return result, err
+            }
+        }
+    }
+    authenticating := func(next Invoker) Invoker {
+        return func(ctx context.Context, callbackName string, dependencies CallbackResults) (interface{}, error) {
+            return next(context.WithValue(ctx, userKey{}, "alice"), callbackName, dependencies)
+        }
+    }
+    getCart := NewContextChanneledCallback(func(ctx context.Context, dependencies CallbackResults) (interface{}, error) {
+        return "cart of " + ctx.Value(userKey{}).(string), nil
+    }, []string{})
+    getCart.Interceptors = []Interceptor{tracing("inner")}
+    channelerInstance := NewChanneler(&CallbackChain{"getCart": getCart})
+    channelerInstance.Interceptors = []Interceptor{tracing("outer"), authenticating}
+
+    _, err := channelerInstance.Run()
+    assert.Nil(t, err)
+    assert.Equal(t, "cart of alice", channelerInstance.Results["getCart"])
+    assert.Equal(t, []string{"outer before getCart", "inner before getCart", "inner after getCart", "outer after getCart"}, traces)
+}
+
+/**
+An interceptor can call the next Invoker several times, every failed attempt being recorded, and its panics are recovered
+ */
+func TestChanneler_RunInterceptorRetriesAndPanics(t *testing.T) {
+    calls := 0
+    channelerInstance := NewChanneler(&CallbackChain{
+        "flaky": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            calls++
+            if (calls == 1) {
+                return nil, errTransient
+            }
+            return "ok", nil
+        }, []string{}),
+        "broken": NewChanneledCallback(noopCallback, []string{}),
+    })
+    channelerInstance.Interceptors = []Interceptor{func(next Invoker) Invoker {
+        return func(ctx context.Context, callbackName string, dependencies CallbackResults) (interface{}, error) {
+            if (callbackName == "broken") {
+                panic("broken interceptor")
+            }
+            result, err := next(ctx, callbackName, dependencies)
+            if (errors.Is(err, errTransient)) {
+                return next(ctx, callbackName, dependencies)
+            }
+            return result, err
+        }
+    }}
+
+    report, _ := channelerInstance.Run()
+    assert.Equal(t, "ok", channelerInstance.Results["flaky"])
+    assert.Equal(t, []error{errTransient}, channelerInstance.AttemptsErrors["flaky"])
+    assert.Equal(t, 2, report.Outcomes["flaky"].Attempts)
+    var panicErr *PanicError
+    assert.True(t, errors.As(channelerInstance.Errors["broken"], &panicErr))
+    assert.Equal(t, "broken interceptor", panicErr.Value)
+}
+
+/**
+OnStart is called for invoked callbacks only, OnFinish for every callback
+ */
+func TestChanneler_RunHooks(t *testing.T) {
+    var hooksMutex sync.Mutex
+    var started []string
+    finished := map[string]NodeStatus{}
+    channelerInstance := NewChanneler(&CallbackChain{
+        "failing": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return nil, errTransient
+        }, []string{}),
+        "ok": NewChanneledCallback(noopCallback, []string{}),
+        "dependent": NewChanneledCallback(noopCallback, []string{"failing", "ok"}),
+    })
+    channelerInstance.Hooks = Hooks{
+        OnStart: func(ctx context.Context, callbackName string) {
+            hooksMutex.Lock()
+            defer hooksMutex.Unlock()
+            started = append(started, callbackName)
+        },
+        OnFinish: func(outcome NodeOutcome) {
+            hooksMutex.Lock()
+            defer hooksMutex.Unlock()
+            finished[outcome.CallbackName] = outcome.Status
+        },
+    }
+
+    channelerInstance.Run()
+    sort.Strings(started)
+    assert.Equal(t, []string{"failing", "ok"}, started)
+    assert.Equal(t, map[string]NodeStatus{"failing": Failed, "ok": Succeeded, "dependent": UpstreamFailed}, finished)
+}
+
+/**
+Wait() does not return while an OnFinish hook is running, even when the run is stopped meanwhile,
+and panicking hooks do not crash the process
+ */
+func TestChanneler_RunHooksFinishBeforeWait(t *testing.T) {
+    var finishedMutex sync.Mutex
+    finished := map[string]bool{}
+    finishing := make(chan struct{})
+    channelerInstance := NewChanneler(&CallbackChain{
+        "fast": NewChanneledCallback(noopCallback, []string{}),
+        "panicking": NewChanneledCallback(noopCallback, []string{}),
+    })
+    channelerInstance.Hooks = Hooks{
+        OnStart: func(ctx context.Context, callbackName string) {
+            if (callbackName == "panicking") {
+                panic("broken OnStart")
+            }
+        },
+        OnFinish: func(outcome NodeOutcome) {
+            if (outcome.CallbackName == "panicking") {
+                panic("broken OnFinish")
+            }
+            close(finishing)
+            time.Sleep(20 * time.Millisecond)
+            finishedMutex.Lock()
+            defer finishedMutex.Unlock()
+            finished[outcome.CallbackName] = true
+        },
+    }
+    execution, err := channelerInstance.Start(context.Background())
+    assert.Nil(t, err)
+    _, err = execution.Await("panicking")
+    assert.Nil(t, err)
+    //stop the run while the OnFinish hook of fast is running, before fast hands its result to the run
+    <-finishing
+    execution.Cancel()
+    report, _ := execution.Wait()
+
+    finishedMutex.Lock()
+    defer finishedMutex.Unlock()
+    assert.True(t, finished["fast"])
+    assert.Equal(t, Succeeded, report.Outcomes["fast"].Status)
+    assert.Equal(t, Succeeded, report.Outcomes["panicking"].Status)
+}
@@ -0,0 +1,67 @@
+package channeler
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "log/slog"
+    "strings"
+    "testing"
+    "github.com/stretchr/testify/assert"
+)
+
+/**
+Every record holds the run's ID, records about a callback hold its name, and failures are warnings
+ */
+func TestChanneler_RunLogger(t *testing.T) {
+    var output bytes.Buffer
+    channelerInstance := NewChanneler(&CallbackChain{
+        "getCart": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return nil, errTransient
+        }, []string{}),
+        "getUser": NewChanneledCallback(noopCallback, []string{}),
+        "checkout": NewChanneledCallback(noopCallback, []string{"getCart", "getUser"}),
+    })
+    channelerInstance.Logger = slog.New(slog.NewJSONHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug}))
+    plan, err := channelerInstance.Compile()
+    assert.Nil(t, err)
+    execution := plan.Start(context.Background())
+    execution.Wait()
+
+    levels := map[string]string{}
+    for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+        var record map[string]interface{}
+        assert.Nil(t, json.Unmarshal([]byte(line), &record))
+        assert.Equal(t, execution.RunID(), record["run_id"])
+        if callbackName, ok := record["callback"].(string); ok {
+            levels[callbackName + " " + record["msg"].(string)] = record["level"].(string)
+        } else {
+            levels[record["msg"].(string)] = record["level"].(string)
+        }
+    }
+    assert.Equal(t, "INFO", levels["run started"])
+    assert.Equal(t, "DEBUG", levels["getCart dependency channel wired"])
+    assert.Equal(t, "DEBUG", levels["checkout waiting for dependencies"])
+    assert.Equal(t, "INFO", levels["getUser callback started"])
+    assert.Equal(t, "INFO", levels["getUser callback succeeded"])
+    assert.Equal(t, "WARN", levels["getCart callback did not succeed"])
+    assert.Equal(t, "DEBUG", levels["getCart propagating failure to dependants"])
+    assert.Equal(t, "WARN", levels["checkout callback not invoked because dependencies failed"])
+    assert.Equal(t, "DEBUG", levels["channels closed"])
+    assert.Equal(t, "WARN", levels["run finished"])
+}
+
+/**
+Nothing is logged without a Logger, and each run gets its own ID
+ */
+func TestChanneler_RunWithoutLogger(t *testing.T) {
+    channelerInstance := NewChanneler(&CallbackChain{"getUser": NewChanneledCallback(noopCallback, []string{})})
+    first, err := channelerInstance.Start(context.Background())
+    assert.Nil(t, err)
+    second, _ := channelerInstance.Start(context.Background())
+    _, err = first.Wait()
+    assert.Nil(t, err)
+    second.Wait()
+    assert.NotEmpty(t, first.RunID())
+    assert.NotEqual(t, first.RunID(), second.RunID())
+}
@@ -0,0 +1,82 @@
+package channeler
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "github.com/stretchr/testify/assert"
+)
+
+/**
+A page can still be rendered without its recommendations block when the recommendations callback fails,
+while a failure of a required dependency still prevents the callback from running
+ */
+func TestChanneler_RunOptionalDependency(t *testing.T) {
+    channelerInstance := NewChanneler(&CallbackChain{
+        "getHeader": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return "header", nil
+        }, []string{}),
+        "getRecommendations": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return nil, errTransient
+        }, []string{}),
+        "renderPage": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            assert.Equal(t, errTransient, dependencies.Err("getRecommendations"))
+            assert.Nil(t, dependencies.Err("getHeader"))
+            return dependencies["getHeader"].(string) + " without recommendations", nil
+        }, []string{"getHeader", "getRecommendations"}).WithOptionalDependencies("getRecommendations"),
+        "renderSidebar": NewChanneledCallback(noopCallback, []string{"getRecommendations"}),
+    })
+
+    _, err := channelerInstance.Run()
+    assert.True(t, errors.Is(err, errTransient))
+    assert.Equal(t, "header without recommendations", channelerInstance.Results["renderPage"])
+    assert.Nil(t, channelerInstance.Errors["renderPage"])
+    assert.True(t, errors.Is(channelerInstance.Errors["renderSidebar"], errTransient))
+}
+
+/**
+A dependency succeeding with an error value as its result is not mistaken for a failed one
+ */
+func TestChanneler_RunOptionalDependencyErrorResult(t *testing.T) {
+    errReport := errors.New("report")
+    channelerInstance := NewChanneler(&CallbackChain{
+        "validate": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return errReport, nil
+        }, []string{}),
+        "getRecommendations": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return nil, errTransient
+        }, []string{}),
+        "renderPage": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            assert.Nil(t, dependencies.Err("validate"))
+            assert.Equal(t, errReport, dependencies["validate"])
+            assert.Equal(t, FailedDependency{errTransient}, dependencies["getRecommendations"])
+            assert.Equal(t, errTransient, dependencies.Err("getRecommendations"))
+            return "page", nil
+        }, []string{"validate", "getRecommendations"}).WithOptionalDependencies("validate", "getRecommendations"),
+    })
+
+    report, _ := channelerInstance.Run()
+    assert.Equal(t, Succeeded, report.Outcomes["validate"].Status)
+    assert.Equal(t, Succeeded, report.Outcomes["renderPage"].Status)
+}
+
+/**
+Typed nodes can be given as optional dependencies too
+ */
+func TestAdd_OptionalDependency(t *testing.T) {
+    channelerInstance := NewChanneler(nil)
+    recommendations := Add(channelerInstance, "getRecommendations", func(ctx context.Context, dependencies CallbackResults) ([]string, error) {
+        return nil, errTransient
+    })
+    page := Add(channelerInstance, "renderPage", func(ctx context.Context, dependencies CallbackResults) (string, error) {
+        if _, isset := Get(dependencies, recommendations); !isset {
+            return "page without recommendations", nil
+        }
+        return "page with recommendations", nil
+    }, Optional(recommendations))
+
+    _, err := channelerInstance.Run()
+    assert.True(t, errors.Is(err, errTransient))
+    rendered, _ := Get(channelerInstance.Results, page)
+    assert.Equal(t, "page without recommendations", rendered)
+}
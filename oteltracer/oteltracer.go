@@ -0,0 +1,75 @@
+/**
+Adapter of OpenTelemetry tracers to the Tracer of a Channeler. It is the only package of the module depending on
+OpenTelemetry : go.opentelemetry.io/otel and go.opentelemetry.io/otel/trace, its tests on go.opentelemetry.io/otel/sdk
+ */
+package oteltracer
+
+import (
+    "context"
+    "fmt"
+
+    channeler "github.com/julianguinard/go-channeler"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/codes"
+    "go.opentelemetry.io/otel/trace"
+)
+
+/**
+Adapt an OpenTelemetry tracer to the Tracer of a Channeler : the spans of a run are children of the span carried by
+the context it is ran with, if any
+ */
+func New(tracer trace.Tracer) channeler.Tracer {
+    return &otelTracer{tracer}
+}
+
+type otelTracer struct {
+    tracer trace.Tracer
+}
+
+type otelSpan struct {
+    span trace.Span
+}
+
+func (tracer *otelTracer) Start(ctx context.Context, spanName string, attributes ...channeler.Attribute) (context.Context, channeler.Span) {
+    ctx, span := tracer.tracer.Start(ctx, spanName, trace.WithAttributes(convert(attributes)...))
+    return ctx, &otelSpan{span}
+}
+
+func (span *otelSpan) SetAttributes(attributes ...channeler.Attribute) {
+    span.span.SetAttributes(convert(attributes)...)
+}
+
+func (span *otelSpan) RecordError(err error) {
+    span.span.RecordError(err)
+    span.span.SetStatus(codes.Error, err.Error())
+}
+
+func (span *otelSpan) End() {
+    span.span.End()
+}
+
+/**
+Convert attributes to OpenTelemetry ones, values of other types being formatted as strings
+ */
+func convert(attributes []channeler.Attribute) []attribute.KeyValue {
+    keyValues := make([]attribute.KeyValue, 0, len(attributes))
+    for _, oneAttribute := range attributes {
+        switch value := oneAttribute.Value.(type) {
+        case string:
+            keyValues = append(keyValues, attribute.String(oneAttribute.Key, value))
+        case bool:
+            keyValues = append(keyValues, attribute.Bool(oneAttribute.Key, value))
+        case int:
+            keyValues = append(keyValues, attribute.Int(oneAttribute.Key, value))
+        case int64:
+            keyValues = append(keyValues, attribute.Int64(oneAttribute.Key, value))
+        case float64:
+            keyValues = append(keyValues, attribute.Float64(oneAttribute.Key, value))
+        case []string:
+            keyValues = append(keyValues, attribute.StringSlice(oneAttribute.Key, value))
+        default:
+            keyValues = append(keyValues, attribute.String(oneAttribute.Key, fmt.Sprint(value)))
+        }
+    }
+    return keyValues
+}
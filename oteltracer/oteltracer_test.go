@@ -0,0 +1,103 @@
+package oteltracer
+
+import (
+    "context"
+    "errors"
+    "testing"
+
+    channeler "github.com/julianguinard/go-channeler"
+    "github.com/stretchr/testify/assert"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/codes"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type point struct {
+    x, y int
+}
+
+func newRecordingTracer() (channeler.Tracer, *tracetest.SpanRecorder) {
+    recorder := tracetest.NewSpanRecorder()
+    provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+    return New(provider.Tracer("channeler")), recorder
+}
+
+/**
+Spans started from the context of another one are its children, attributes being converted by type
+ */
+func TestTracer_Spans(t *testing.T) {
+    tracer, recorder := newRecordingTracer()
+    ctx, parent := tracer.Start(context.Background(), "parent", channeler.Attribute{Key: "name", Value: "run"})
+    _, child := tracer.Start(ctx, "child")
+    child.SetAttributes(
+        channeler.Attribute{Key: "string", Value: "value"},
+        channeler.Attribute{Key: "bool", Value: true},
+        channeler.Attribute{Key: "int", Value: 3},
+        channeler.Attribute{Key: "int64", Value: int64(4)},
+        channeler.Attribute{Key: "float64", Value: 1.5},
+        channeler.Attribute{Key: "strings", Value: []string{"a", "b"}},
+        channeler.Attribute{Key: "other", Value: point{1, 2}},
+    )
+    child.End()
+    parent.End()
+
+    spans := recorder.Ended()
+    assert.Len(t, spans, 2)
+    childSpan, parentSpan := spans[0], spans[1]
+    assert.Equal(t, "child", childSpan.Name())
+    assert.Equal(t, parentSpan.SpanContext().SpanID(), childSpan.Parent().SpanID())
+    assert.Equal(t, parentSpan.SpanContext().TraceID(), childSpan.SpanContext().TraceID())
+    assert.Equal(t, []attribute.KeyValue{attribute.String("name", "run")}, parentSpan.Attributes())
+    assert.Equal(t, []attribute.KeyValue{
+        attribute.String("string", "value"),
+        attribute.Bool("bool", true),
+        attribute.Int("int", 3),
+        attribute.Int64("int64", 4),
+        attribute.Float64("float64", 1.5),
+        attribute.StringSlice("strings", []string{"a", "b"}),
+        attribute.String("other", "{1 2}"),
+    }, childSpan.Attributes())
+    assert.Equal(t, codes.Unset, childSpan.Status().Code)
+}
+
+/**
+A recorded error is added as an event and sets the status of the span
+ */
+func TestTracer_RecordError(t *testing.T) {
+    tracer, recorder := newRecordingTracer()
+    _, span := tracer.Start(context.Background(), "failing")
+    span.RecordError(errors.New("broken"))
+    span.End()
+
+    ended := recorder.Ended()[0]
+    assert.Equal(t, codes.Error, ended.Status().Code)
+    assert.Equal(t, "broken", ended.Status().Description)
+    assert.Len(t, ended.Events(), 1)
+    assert.Equal(t, "exception", ended.Events()[0].Name)
+}
+
+/**
+The spans of a run are children of the span carried by the context it is ran with
+ */
+func TestTracer_RunSpans(t *testing.T) {
+    tracer, recorder := newRecordingTracer()
+    channelerInstance := channeler.NewChanneler(&channeler.CallbackChain{
+        "getUser": channeler.NewChanneledCallback(func(dependencies channeler.CallbackResults) (interface{}, error) {
+            return "user", nil
+        }, []string{}),
+    })
+    channelerInstance.Tracer = tracer
+    ctx, request := tracer.Start(context.Background(), "request")
+    _, err := channelerInstance.RunContext(ctx)
+    assert.Nil(t, err)
+    request.End()
+
+    spans := map[string]sdktrace.ReadOnlySpan{}
+    for _, span := range recorder.Ended() {
+        spans[span.Name()] = span
+    }
+    assert.Equal(t, spans["request"].SpanContext().SpanID(), spans["channeler.run"].Parent().SpanID())
+    assert.Equal(t, spans["channeler.run"].SpanContext().SpanID(), spans["getUser"].Parent().SpanID())
+    assert.Contains(t, spans["getUser"].Attributes(), attribute.String("channeler.status", "succeeded"))
+}
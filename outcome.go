@@ -0,0 +1,226 @@
+package channeler
+
+import (
+    "context"
+    "sort"
+    "sync"
+    "time"
+)
+
+type NodeStatus int
+
+const (
+    //waiting for its dependencies or for a free slot
+    Pending NodeStatus = iota
+    //its callback function is being invoked
+    Running
+    Succeeded
+    //its callback function returned an error or panicked
+    Failed
+    //not invoked because one of its required dependencies did not succeed
+    UpstreamFailed
+    //not invoked because the run was cancelled or timed out while it was still waiting for its dependencies
+    Skipped
+    //cancelled while ready or running
+    Cancelled
+    //exceeded its own Timeout, or the Channeler.Timeout deadline while ready or running
+    TimedOut
+)
+
+var nodeStatusNames = []string{"pending", "running", "succeeded", "failed", "upstream failed", "skipped", "cancelled", "timed out"}
+
+func (status NodeStatus) String() string {
+    if (status < 0 || int(status) >= len(nodeStatusNames)) {
+        return "unknown"
+    }
+    return nodeStatusNames[status]
+}
+
+/**
+Whether a callback with this status will not change anymore
+ */
+func (status NodeStatus) IsFinal() bool {
+    return status != Pending && status != Running
+}
+
+/**
+What happened to a callback of the chain during a run
+ */
+type NodeOutcome struct {
+    CallbackName string
+    Status       NodeStatus
+    //result of the callback function, which may be a partial result when Err is set
+    Value        interface{}
+    Err          error
+    //sorted names of the required dependencies whose failure prevented it from running, as known when it stopped waiting for them
+    FailedDependencies []string
+    //when its dependencies were satisfied, zero if they never were
+    ReadyAt      time.Time
+    //when its callback function was first invoked, zero if it never was
+    StartedAt    time.Time
+    //when its status became final
+    FinishedAt   time.Time
+    //number of times its callback function was invoked
+    Attempts     int
+    //errors of each failed attempt, in order
+    AttemptsErrors []error
+}
+
+/**
+Time spent invoking the callback function, zero if it was never invoked
+ */
+func (outcome *NodeOutcome) Duration() time.Duration {
+    if (outcome.StartedAt.IsZero()) {
+        return 0
+    }
+    return outcome.FinishedAt.Sub(outcome.StartedAt)
+}
+
+/**
+Returned by Run() : the outcome of every callback of the chain, by callback name
+ */
+type RunReport struct {
+    StartedAt  time.Time
+    FinishedAt time.Time
+    Outcomes   map[string]*NodeOutcome
+}
+
+func (report *RunReport) Duration() time.Duration {
+    return report.FinishedAt.Sub(report.StartedAt)
+}
+
+/**
+Return the sorted names of the callbacks having one of the given statuses
+ */
+func (report *RunReport) CallbackNames(statuses ...NodeStatus) []string {
+    var callbackNames []string
+    for callbackName, outcome := range report.Outcomes {
+        for _, status := range statuses {
+            if (outcome.Status == status) {
+                callbackNames = append(callbackNames, callbackName)
+                break
+            }
+        }
+    }
+    sort.Strings(callbackNames)
+    return callbackNames
+}
+
+/**
+Whether every callback of the chain succeeded
+ */
+func (report *RunReport) Succeeded() bool {
+    for _, outcome := range report.Outcomes {
+        if (outcome.Status != Succeeded) {
+            return false
+        }
+    }
+    return true
+}
+
+/**
+Return the result of every callback by callback name, nil for the ones which did not succeed :
+partial results of failed callbacks are only exposed by their NodeOutcome
+ */
+func (report *RunReport) Results() CallbackResults {
+    results := CallbackResults{}
+    for callbackName, outcome := range report.Outcomes {
+        results[callbackName] = nil
+        if (outcome.Err == nil) {
+            results[callbackName] = outcome.Value
+        }
+    }
+    return results
+}
+
+/**
+Return the error of every callback by callback name, nil for the ones which succeeded
+ */
+func (report *RunReport) Errors() map[string]error {
+    errs := map[string]error{}
+    for callbackName, outcome := range report.Outcomes {
+        errs[callbackName] = outcome.Err
+    }
+    return errs
+}
+
+/**
+Return the sorted names of the callbacks which Failed or TimedOut on their own : the root causes of the UpstreamFailed ones
+ */
+func (report *RunReport) RootCauses() []string {
+    return report.CallbackNames(Failed, TimedOut)
+}
+
+/**
+Outcomes of a run being executed, updated by each callback's goroutine
+ */
+type outcomesTable struct {
+    mutex    sync.Mutex
+    outcomes map[string]*NodeOutcome
+    //set by snapshot() : the outcomes of the run are known for good
+    frozen   bool
+}
+
+func newOutcomesTable(callbackNames []string) *outcomesTable {
+    table := &outcomesTable{outcomes: map[string]*NodeOutcome{}}
+    for _, callbackName := range callbackNames {
+        table.outcomes[callbackName] = &NodeOutcome{CallbackName: callbackName, Status: Pending}
+    }
+    return table
+}
+
+/**
+Apply updateFunction to the outcome of a callback unless the run is already over, and tell whether it was
+ */
+func (table *outcomesTable) update(callbackName string, updateFunction func(outcome *NodeOutcome)) bool {
+    table.mutex.Lock()
+    defer table.mutex.Unlock()
+    if (table.frozen) {
+        return false
+    }
+    updateFunction(table.outcomes[callbackName])
+    return true
+}
+
+/**
+Return a copy of the current outcome of a callback
+ */
+func (table *outcomesTable) get(callbackName string) NodeOutcome {
+    table.mutex.Lock()
+    defer table.mutex.Unlock()
+    return *table.outcomes[callbackName]
+}
+
+/**
+Copy the outcomes and freeze the table so that goroutines still running after the run is over cannot alter them.
+Callbacks which are not over yet are marked as interrupted by ctx being done
+ */
+func (table *outcomesTable) snapshot(ctx context.Context, runStartedAt time.Time) map[string]*NodeOutcome {
+    table.mutex.Lock()
+    defer table.mutex.Unlock()
+    table.frozen = true
+    outcomes := make(map[string]*NodeOutcome, len(table.outcomes))
+    for callbackName, outcome := range table.outcomes {
+        outcomeCopy := *outcome
+        if (!outcomeCopy.Status.IsFinal()) {
+            outcomeCopy.Status = interruptedStatus(ctx, !outcomeCopy.ReadyAt.IsZero())
+            outcomeCopy.Err = interruption(ctx, callbackName, runStartedAt)
+            outcomeCopy.FinishedAt = time.Now()
+        }
+        outcomes[callbackName] = &outcomeCopy
+    }
+    return outcomes
+}
+
+/**
+Status of a callback which could not run or finish because the run's context is done
+ */
+func interruptedStatus(ctx context.Context, ready bool) NodeStatus {
+    if (!ready) {
+        return Skipped
+    }
+    if (ctx.Err() == context.DeadlineExceeded) {
+        return TimedOut
+    }
+    return Cancelled
+}
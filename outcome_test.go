@@ -0,0 +1,92 @@
+package channeler
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "time"
+    "github.com/stretchr/testify/assert"
+)
+
+/**
+Each callback's outcome tells whether it failed on its own, because of an upstream failure or never ran
+ */
+func TestChanneler_RunReport(t *testing.T) {
+    flaky := NewContextChanneledCallback(func(ctx context.Context, dependencies CallbackResults) (interface{}, error) {
+        if (AttemptFromContext(ctx) == 1) {
+            return nil, errTransient
+        }
+        return "flaky", nil
+    }, []string{})
+    flaky.Retry = &RetryPolicy{MaxAttempts: 2}
+    hung := NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+        time.Sleep(time.Second)
+        return nil, nil
+    }, []string{})
+    hung.Timeout = 10 * time.Millisecond
+    channelerInstance := NewChanneler(&CallbackChain{
+        "succeeding": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            time.Sleep(10 * time.Millisecond)
+            return "ok", nil
+        }, []string{}),
+        "flaky": flaky,
+        "failing": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return nil, errTransient
+        }, []string{}),
+        "upstreamFailed": NewChanneledCallback(noopCallback, []string{"succeeding", "failing"}),
+        "transitivelyFailed": NewChanneledCallback(noopCallback, []string{"upstreamFailed"}),
+        "hung": hung,
+    })
+
+    report, err := channelerInstance.Run()
+    assert.True(t, errors.Is(err, errTransient))
+    assert.False(t, report.Succeeded())
+    assert.Equal(t, []string{"flaky", "succeeding"}, report.CallbackNames(Succeeded))
+    assert.Equal(t, []string{"failing"}, report.CallbackNames(Failed))
+    assert.Equal(t, []string{"transitivelyFailed", "upstreamFailed"}, report.CallbackNames(UpstreamFailed))
+    assert.Equal(t, []string{"hung"}, report.CallbackNames(TimedOut))
+
+    succeeding := report.Outcomes["succeeding"]
+    assert.Equal(t, "ok", succeeding.Value)
+    assert.Equal(t, 1, succeeding.Attempts)
+    assert.GreaterOrEqual(t, succeeding.Duration(), 10 * time.Millisecond)
+    assert.False(t, succeeding.ReadyAt.After(succeeding.StartedAt))
+    assert.False(t, report.StartedAt.After(succeeding.ReadyAt))
+    assert.False(t, report.FinishedAt.Before(succeeding.FinishedAt))
+
+    assert.Equal(t, 2, report.Outcomes["flaky"].Attempts)
+    assert.Equal(t, []error{errTransient}, report.Outcomes["flaky"].AttemptsErrors)
+    assert.True(t, errors.Is(report.Outcomes["upstreamFailed"].Err, errTransient))
+    assert.True(t, report.Outcomes["upstreamFailed"].StartedAt.IsZero())
+    assert.Equal(t, 0, report.Outcomes["upstreamFailed"].Attempts)
+    assert.Equal(t, "upstream failed", UpstreamFailed.String())
+
+    //Results and Errors are kept populated for compatibility
+    assert.Equal(t, "ok", channelerInstance.Results["succeeding"])
+    assert.True(t, errors.Is(channelerInstance.Errors["transitivelyFailed"], errTransient))
+}
+
+/**
+When the run is stopped, running callbacks are cancelled whereas those still waiting for their dependencies are skipped
+ */
+func TestChanneler_RunReportInterrupted(t *testing.T) {
+    channelerInstance := NewChanneler(&CallbackChain{
+        "failing": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            time.Sleep(10 * time.Millisecond)
+            return nil, errTransient
+        }, []string{}),
+        "running": NewContextChanneledCallback(func(ctx context.Context, dependencies CallbackResults) (interface{}, error) {
+            <-ctx.Done()
+            return nil, ctx.Err()
+        }, []string{}),
+        "waiting": NewChanneledCallback(noopCallback, []string{"running"}),
+    })
+    channelerInstance.ErrorPolicy = FailFast
+
+    report, err := channelerInstance.Run()
+    assert.NotNil(t, err)
+    assert.Equal(t, Failed, report.Outcomes["failing"].Status)
+    assert.Equal(t, Cancelled, report.Outcomes["running"].Status)
+    assert.Equal(t, Skipped, report.Outcomes["waiting"].Status)
+    assert.True(t, report.Outcomes["waiting"].ReadyAt.IsZero())
+}
@@ -0,0 +1,43 @@
+package channeler
+
+import (
+    "context"
+    "fmt"
+    "runtime/debug"
+)
+
+/**
+Stored for callbacks whose function panicked, unless Channeler.CrashOnPanic is set.
+Value is what was recovered and Stack the stack trace of the panicking goroutine
+ */
+type PanicError struct {
+    CallbackName string
+    Value interface{}
+    Stack []byte
+}
+func(err *PanicError) Error() string {
+    return fmt.Sprintf("%s panicked : %v", err.CallbackName, err.Value)
+}
+/**
+Give access to the recovered value when the callback panicked with an error
+ */
+func(err *PanicError) Unwrap() error {
+    if recoveredError, isOfTypeError := err.Value.(error); isOfTypeError {
+        return recoveredError
+    }
+    return nil
+}
+
+/**
+Wrap a callback function so that a panic is returned as a PanicError instead of crashing the process
+ */
+func recoverPanics(callbackName string, callbackFunction ChanneledCallbackContextFunction) ChanneledCallbackContextFunction {
+    return func(ctx context.Context, dependencies CallbackResults) (result interface{}, err error) {
+        defer func() {
+            if recovered := recover(); recovered != nil {
+                result, err = nil, &PanicError{callbackName, recovered, debug.Stack()}
+            }
+        }()
+        return callbackFunction(ctx, dependencies)
+    }
+}
@@ -0,0 +1,59 @@
+package channeler
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "time"
+    "github.com/stretchr/testify/assert"
+)
+
+/**
+A panicking callback is stored as a PanicError which propagates to its dependents, other callbacks being unaffected
+ */
+func TestChanneler_RunPanicRecovery(t *testing.T) {
+    timedPanic := NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+        panic(errTransient)
+    }, []string{})
+    timedPanic.Timeout = time.Second
+    channelerInstance := NewChanneler(&CallbackChain{
+        "panicking": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            var fruits map[string]string
+            fruits["apple"] = "red"
+            return fruits, nil
+        }, []string{}),
+        "timedPanic": timedPanic,
+        "dependent": NewChanneledCallback(noopCallback, []string{"panicking"}),
+        "independent": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return "ok", nil
+        }, []string{}),
+    })
+
+    _, err := channelerInstance.Run()
+    assert.True(t, errors.Is(err, errTransient))
+    var panicErr *PanicError
+    assert.True(t, errors.As(channelerInstance.Errors["panicking"], &panicErr))
+    assert.Equal(t, "panicking", panicErr.CallbackName)
+    assert.Contains(t, string(panicErr.Stack), "panic_test.go")
+    assert.True(t, errors.Is(channelerInstance.Errors["dependent"], channelerInstance.Errors["panicking"]))
+    assert.Equal(t, "ok", channelerInstance.Results["independent"])
+
+    //panics inside callbacks ran with a Timeout happen in another goroutine, and unwrap to the recovered error
+    assert.True(t, errors.As(channelerInstance.Errors["timedPanic"], &panicErr))
+    assert.Equal(t, errTransient, panicErr.Value)
+    assert.True(t, errors.Is(channelerInstance.Errors["timedPanic"], errTransient))
+}
+
+/**
+Teams preferring to crash can opt out of the recovery
+ */
+func TestChanneledCallback_InvokeCrashOnPanic(t *testing.T) {
+    channeledCallback := NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+        panic("boom")
+    }, []string{})
+    assert.PanicsWithValue(t, "boom", func() {
+        channeledCallback.invoke(context.Background(), "crashing", CallbackResults{}, nil, true)
+    })
+    _, _, err := channeledCallback.invoke(context.Background(), "crashing", CallbackResults{}, nil, false)
+    assert.Equal(t, "crashing panicked : boom", err.Error())
+}
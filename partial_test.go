@@ -0,0 +1,97 @@
+package channeler
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "github.com/stretchr/testify/assert"
+)
+
+/**
+A batch callback failing on a few items keeps its partial result next to its error, and only the dependents
+accepting partial results consume it
+ */
+func TestChanneler_RunPartialResults(t *testing.T) {
+    channelerInstance := NewChanneler(&CallbackChain{
+        "fetchBatch": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return []string{"apple", "banana"}, errTransient
+        }, []string{}),
+        "countFruits": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            partialResult := dependencies["fetchBatch"].(PartialResult)
+            assert.Equal(t, errTransient, dependencies.Err("fetchBatch"))
+            return len(partialResult.Value.([]string)), nil
+        }, []string{"fetchBatch"}).WithPartialDependencies("fetchBatch"),
+        "strictCount": NewChanneledCallback(noopCallback, []string{"fetchBatch"}),
+    })
+
+    report, err := channelerInstance.Run()
+    assert.True(t, errors.Is(err, errTransient))
+    assert.Equal(t, Failed, report.Outcomes["fetchBatch"].Status)
+    assert.Equal(t, []string{"apple", "banana"}, report.Outcomes["fetchBatch"].Value)
+    assert.Equal(t, errTransient, report.Outcomes["fetchBatch"].Err)
+    assert.Nil(t, channelerInstance.Results["fetchBatch"])
+
+    assert.Equal(t, Succeeded, report.Outcomes["countFruits"].Status)
+    assert.Equal(t, 2, channelerInstance.Results["countFruits"])
+    assert.Equal(t, UpstreamFailed, report.Outcomes["strictCount"].Status)
+}
+
+/**
+A nil slice or pointer returned as an interface{} along with an error is no partial result, the same way as for Add()
+ */
+func TestChanneler_RunTypedNilIsNoPartialResult(t *testing.T) {
+    var nilFruits []string
+    var nilCount *int
+    channelerInstance := NewChanneler(&CallbackChain{
+        "fetchNothing": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return nilFruits, errTransient
+        }, []string{}),
+        "countNothing": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return nilCount, errTransient
+        }, []string{}),
+        "countFruits": NewChanneledCallback(noopCallback, []string{"fetchNothing"}).WithPartialDependencies("fetchNothing"),
+        "sumCounts": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            _, isPartial := dependencies["countNothing"].(PartialResult)
+            assert.False(t, isPartial)
+            assert.Equal(t, FailedDependency{errTransient}, dependencies["countNothing"])
+            return 0, nil
+        }, []string{"countNothing"}).WithPartialDependencies("countNothing").WithOptionalDependencies("countNothing"),
+    })
+
+    report, _ := channelerInstance.Run()
+    assert.Equal(t, UpstreamFailed, report.Outcomes["countFruits"].Status)
+    assert.Equal(t, Succeeded, report.Outcomes["sumCounts"].Status)
+}
+
+/**
+Without any partial result, a dependency accepting partial results is still a required one
+ */
+func TestAdd_AcceptPartial(t *testing.T) {
+    channelerInstance := NewChanneler(nil)
+    batch := Add(channelerInstance, "fetchBatch", func(ctx context.Context, dependencies CallbackResults) ([]string, error) {
+        return []string{"apple"}, errTransient
+    })
+    empty := Add(channelerInstance, "fetchNothing", func(ctx context.Context, dependencies CallbackResults) ([]string, error) {
+        return nil, errTransient
+    })
+    count := Add(channelerInstance, "countFruits", func(ctx context.Context, dependencies CallbackResults) (int, error) {
+        fruits, isset := Get(dependencies, batch)
+        assert.True(t, isset)
+        assert.Equal(t, errTransient, dependencies.Err(batch.Name()))
+        return len(fruits), nil
+    }, AcceptPartial(batch))
+    Add(channelerInstance, "countNothing", func(ctx context.Context, dependencies CallbackResults) (int, error) {
+        return 0, nil
+    }, Optional(AcceptPartial(empty)))
+    strict := Add(channelerInstance, "strictCount", func(ctx context.Context, dependencies CallbackResults) (int, error) {
+        return 0, nil
+    }, AcceptPartial(empty))
+
+    report, err := channelerInstance.Run()
+    assert.True(t, errors.Is(err, errTransient))
+    fruitsNb, _ := Get(channelerInstance.Results, count)
+    assert.Equal(t, 1, fruitsNb)
+    assert.Nil(t, report.Outcomes["fetchNothing"].Value)
+    assert.Equal(t, Succeeded, report.Outcomes["countNothing"].Status)
+    assert.Equal(t, UpstreamFailed, report.Outcomes[strict.Name()].Status)
+}
@@ -0,0 +1,213 @@
+package channeler
+
+import (
+    "context"
+    "log/slog"
+    "time"
+)
+
+/**
+Validated and frozen form of a Channeler's CallbackChain and settings, returned by Compile().
+Later changes to the Channeler or to its CallbackChain do not alter it, and it can be ran any number of times,
+including concurrently : each run gets its own channels, outcomes and results, exposed by the RunReport it returns
+ */
+type Plan struct {
+    timeout         time.Duration
+    maxConcurrency  int
+    readyQueueOrder ReadyQueueOrder
+    crashOnPanic    bool
+    errorPolicy     ErrorPolicy
+    interceptors    []Interceptor
+    hooks           Hooks
+    logger          *slog.Logger
+    tracer          Tracer
+    //sorted names of the callbacks
+    callbackNames   []string
+    nodes           map[string]*planNode
+}
+
+/**
+A callback of a Plan along with its adjacency lists
+ */
+type planNode struct {
+    //copy of the ChanneledCallback taken by Compile()
+    channeledCallback *ChanneledCallback
+    //sorted names of the callbacks it depends on
+    dependencies      []string
+    //sorted names of the callbacks depending on it
+    dependants        []string
+}
+
+/**
+Channels and outcomes of one run of a Plan
+ */
+type runState struct {
+    //fed by the callbacks of the plan once they are over, by callback name
+    results  channelsMap
+    //dependants' inboxes each callback writes its result or error into once it is over, by callback name
+    feeds    map[string]channelsMap
+    //fed by the dependencies of each callback, by callback name
+    inboxes  channelsMap
+    outcomes *outcomesTable
+}
+
+/**
+Validate the CallbackChain and freeze it along with the Channeler's settings into a Plan
+ */
+func (channeler *Channeler) Compile() (*Plan, error) {
+    if err := channeler.Validate(); err != nil {
+        return nil, err
+    }
+    plan := &Plan{
+        timeout: channeler.Timeout,
+        maxConcurrency: channeler.MaxConcurrency,
+        readyQueueOrder: channeler.ReadyQueueOrder,
+        crashOnPanic: channeler.CrashOnPanic,
+        errorPolicy: channeler.ErrorPolicy,
+        interceptors: append([]Interceptor{}, channeler.Interceptors...),
+        hooks: channeler.Hooks,
+        logger: channeler.Logger,
+        tracer: channeler.Tracer,
+        nodes: map[string]*planNode{},
+    }
+    if (channeler.CallbackChain == nil) {
+        return plan, nil
+    }
+    callbackChain := *channeler.CallbackChain
+    plan.callbackNames = callbackChain.sortedNames()
+    for _, callbackName := range plan.callbackNames {
+        channeledCallback := *callbackChain[callbackName]
+        channeledCallback.DependenciesNames = append([]string{}, channeledCallback.DependenciesNames...)
+        channeledCallback.Interceptors = append([]Interceptor{}, channeledCallback.Interceptors...)
+        if (channeledCallback.Retry != nil) {
+            retryPolicy := *channeledCallback.Retry
+            channeledCallback.Retry = &retryPolicy
+        }
+        if (channeledCallback.DependenciesOptions != nil) {
+            dependenciesOptions := make(map[string]DependencyOptions, len(channeledCallback.DependenciesOptions))
+            for dependencyName, dependencyOptions := range channeledCallback.DependenciesOptions {
+                dependenciesOptions[dependencyName] = dependencyOptions
+            }
+            channeledCallback.DependenciesOptions = dependenciesOptions
+        }
+        plan.nodes[callbackName] = &planNode{
+            channeledCallback: &channeledCallback,
+            dependencies: callbackChain.sortedDependencies(callbackName),
+        }
+    }
+    //callback names being sorted, the dependants are appended in order
+    for _, callbackName := range plan.callbackNames {
+        for _, dependencyName := range plan.nodes[callbackName].dependencies {
+            plan.nodes[dependencyName].dependants = append(plan.nodes[dependencyName].dependants, callbackName)
+        }
+    }
+    return plan, nil
+}
+
+/**
+Return the sorted names of the callbacks of the plan
+ */
+func (plan *Plan) CallbackNames() []string {
+    return append([]string{}, plan.callbackNames...)
+}
+
+/**
+Return the sorted names of the callbacks the given callback depends on
+ */
+func (plan *Plan) Dependencies(callbackName string) []string {
+    if node, isset := plan.nodes[callbackName]; isset {
+        return append([]string{}, node.dependencies...)
+    }
+    return nil
+}
+
+/**
+Return the sorted names of the callbacks depending on the given callback
+ */
+func (plan *Plan) Dependants(callbackName string) []string {
+    if node, isset := plan.nodes[callbackName]; isset {
+        return append([]string{}, node.dependants...)
+    }
+    return nil
+}
+
+/**
+Return the names of the callbacks of the plan, each one after its dependencies, in the same order for every call
+ */
+func (plan *Plan) topologicalOrder() []string {
+    pendingDependencies := map[string]int{}
+    var ready, order []string
+    for _, callbackName := range plan.callbackNames {
+        pendingDependencies[callbackName] = len(plan.nodes[callbackName].dependencies)
+        if (pendingDependencies[callbackName] == 0) {
+            ready = append(ready, callbackName)
+        }
+    }
+    for len(ready) > 0 {
+        callbackName := ready[0]
+        ready = ready[1:]
+        order = append(order, callbackName)
+        for _, dependantName := range plan.nodes[callbackName].dependants {
+            pendingDependencies[dependantName]--
+            if (pendingDependencies[dependantName] == 0) {
+                ready = append(ready, dependantName)
+            }
+        }
+    }
+    return order
+}
+
+/**
+Create the channels of a run of the plan : an inbox per callback which is fed by its dependencies without blocking,
+and a result channel per callback
+ */
+func (plan *Plan) newRunState(logger *slog.Logger) *runState {
+    state := &runState{
+        results: channelsMap{},
+        feeds: map[string]channelsMap{},
+        inboxes: channelsMap{},
+        outcomes: newOutcomesTable(plan.callbackNames),
+    }
+    for callbackName, node := range plan.nodes {
+        state.results[callbackName] = make(envelopeChannel, 1)
+        if (len(node.dependencies) > 0) {
+            state.inboxes[callbackName] = make(envelopeChannel, len(node.dependencies))
+        }
+    }
+    for callbackName, node := range plan.nodes {
+        state.feeds[callbackName] = channelsMap{}
+        for _, dependantName := range node.dependants {
+            //expose the dependant's inbox into current callback's feed channels
+            state.feeds[callbackName][dependantName] = state.inboxes[dependantName]
+            logger.Debug("dependency channel wired", "callback", callbackName, "dependant", dependantName)
+        }
+    }
+    return state
+}
+
+/**
+Close each of the channels of the run
+ */
+func (state *runState) closeAllChannels(logger *slog.Logger) {
+    for _, oneChannel := range state.results {
+        close(oneChannel)
+    }
+    for _, oneChannel := range state.inboxes {
+        close(oneChannel)
+    }
+    logger.Debug("channels closed", "result_channels", len(state.results), "inboxes", len(state.inboxes))
+}
+
+/**
+Same as Channeler.Run(), the plan being already validated
+ */
+func (plan *Plan) Run() (*RunReport, error) {
+    return plan.RunContext(context.Background())
+}
+
+/**
+Same as Channeler.RunContext(), the plan being already validated
+ */
+func (plan *Plan) RunContext(ctx context.Context) (*RunReport, error) {
+    return plan.Start(ctx).Wait()
+}
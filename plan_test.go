@@ -0,0 +1,98 @@
+package channeler
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "sync"
+    "testing"
+    "github.com/stretchr/testify/assert"
+)
+
+type requestIDKey struct{}
+
+/**
+A compiled plan can be ran from many goroutines at once, each run getting its own results
+ */
+func TestPlan_ConcurrentRuns(t *testing.T) {
+    channelerInstance := NewChanneler(&CallbackChain{
+        "getUser": NewContextChanneledCallback(func(ctx context.Context, dependencies CallbackResults) (interface{}, error) {
+            return fmt.Sprintf("user%d", ctx.Value(requestIDKey{})), nil
+        }, []string{}),
+        "getCart": NewContextChanneledCallback(func(ctx context.Context, dependencies CallbackResults) (interface{}, error) {
+            if (ctx.Value(requestIDKey{}).(int) % 2 == 1) {
+                return nil, errTransient
+            }
+            return fmt.Sprintf("cart%d", ctx.Value(requestIDKey{})), nil
+        }, []string{}),
+        "renderPage": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return dependencies["getUser"].(string) + " " + dependencies["getCart"].(string), nil
+        }, []string{"getUser", "getCart"}),
+    })
+    plan, err := channelerInstance.Compile()
+    assert.Nil(t, err)
+
+    var waitGroup sync.WaitGroup
+    for requestID := 0; requestID < 20; requestID++ {
+        waitGroup.Add(1)
+        go func(requestID int) {
+            defer waitGroup.Done()
+            report, err := plan.RunContext(context.WithValue(context.Background(), requestIDKey{}, requestID))
+            if (requestID % 2 == 1) {
+                assert.True(t, errors.Is(err, errTransient))
+                assert.Nil(t, report.Results()["renderPage"])
+                assert.Equal(t, UpstreamFailed, report.Outcomes["renderPage"].Status)
+                return
+            }
+            assert.Nil(t, err)
+            assert.Equal(t, fmt.Sprintf("user%d cart%d", requestID, requestID), report.Results()["renderPage"])
+            assert.Nil(t, report.Errors()["renderPage"])
+        }(requestID)
+    }
+    waitGroup.Wait()
+}
+
+/**
+Changing the Channeler or its CallbackChain after Compile() does not alter the plan
+ */
+func TestPlan_Immutable(t *testing.T) {
+    channelerInstance := NewChanneler(&CallbackChain{
+        "a": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return "a", nil
+        }, []string{}),
+        "b": NewChanneledCallback(noopCallback, []string{"a"}),
+        "c": NewChanneledCallback(noopCallback, []string{"a", "b"}),
+    })
+    plan, err := channelerInstance.Compile()
+    assert.Nil(t, err)
+    assert.Equal(t, []string{"a", "b", "c"}, plan.CallbackNames())
+    assert.Equal(t, []string{"a", "b"}, plan.Dependencies("c"))
+    assert.Equal(t, []string{"b", "c"}, plan.Dependants("a"))
+    assert.Nil(t, plan.Dependants("missing"))
+
+    (*channelerInstance.CallbackChain)["a"].CallbackFunction = func(dependencies CallbackResults) (interface{}, error) {
+        return nil, errTransient
+    }
+    (*channelerInstance.CallbackChain)["b"].DependenciesNames[0] = "c"
+    delete(*channelerInstance.CallbackChain, "c")
+    channelerInstance.ErrorPolicy = FailFast
+
+    report, err := plan.Run()
+    assert.Nil(t, err)
+    assert.Equal(t, "a", report.Results()["a"])
+    assert.Len(t, report.Outcomes, 3)
+
+    //the retry policy is copied as well
+    calls := 0
+    flaky := NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+        calls++
+        return nil, errTransient
+    }, []string{})
+    flaky.Retry = &RetryPolicy{MaxAttempts: 1}
+    plan, err = NewChanneler(&CallbackChain{"flaky": flaky}).Compile()
+    assert.Nil(t, err)
+    flaky.Retry.MaxAttempts = 4
+    report, _ = plan.Run()
+    assert.Equal(t, 1, calls)
+    assert.Equal(t, 1, report.Outcomes["flaky"].Attempts)
+}
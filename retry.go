@@ -0,0 +1,109 @@
+package channeler
+
+import (
+    "context"
+    "math"
+    "math/rand"
+    "time"
+)
+
+type BackoffStrategy int
+
+const (
+    //wait RetryPolicy.Delay between each attempt
+    ConstantBackoff BackoffStrategy = iota
+    //double the wait between each attempt, starting from RetryPolicy.Delay and capped by RetryPolicy.MaxDelay if set
+    ExponentialBackoff
+)
+
+/**
+Describes how a failing ChanneledCallback is tried again before its error is stored and propagated.
+Each attempt gets the whole ChanneledCallback.Timeout, and waiting between attempts stops as soon as the run's context is done
+ */
+type RetryPolicy struct {
+    //total number of attempts, first one included : 0 or 1 means that the callback is never retried
+    MaxAttempts int
+    Backoff     BackoffStrategy
+    //wait before the 2nd attempt
+    Delay       time.Duration
+    //upper bound of the wait between attempts, zero means no bound
+    MaxDelay    time.Duration
+    //fraction (between 0 and 1, clamped to it) of each wait which is randomly removed, so that callbacks failing together do not retry together
+    Jitter      float64
+    //tells whether an error is worth another attempt. When nil, every error is retried
+    Retryable   func(err error) bool
+}
+
+type attemptContextKey struct{}
+
+/**
+Return the number (starting at 1) of the current attempt of the callback which received ctx
+ */
+func AttemptFromContext(ctx context.Context) int {
+    if attempt, isset := ctx.Value(attemptContextKey{}).(int); isset {
+        return attempt
+    }
+    return 1
+}
+
+//longest wait between attempts, which exponential backoffs without MaxDelay saturate at
+const maxDelay = time.Duration(math.MaxInt64)
+
+/**
+Compute the wait before the given attempt number (2 for the first retry)
+ */
+func (retryPolicy *RetryPolicy) delay(attempt int) time.Duration {
+    delay := retryPolicy.Delay
+    if (retryPolicy.Backoff == ExponentialBackoff) {
+        for retry := 2; retry < attempt; retry++ {
+            //saturate instead of overflowing into a negative or zero wait
+            if (delay > maxDelay / 2) {
+                delay = maxDelay
+                break
+            }
+            delay *= 2
+            if (retryPolicy.MaxDelay > 0 && delay >= retryPolicy.MaxDelay) {
+                break
+            }
+        }
+    }
+    if (retryPolicy.MaxDelay > 0 && delay > retryPolicy.MaxDelay) {
+        delay = retryPolicy.MaxDelay
+    }
+    if jitter := min(retryPolicy.Jitter, 1); jitter > 0 {
+        delay -= time.Duration(float64(delay) * jitter * rand.Float64())
+    }
+    return delay
+}
+
+func (retryPolicy *RetryPolicy) shouldRetry(attempt int, err error) bool {
+    if (retryPolicy == nil || attempt >= retryPolicy.MaxAttempts) {
+        return false
+    }
+    return retryPolicy.Retryable == nil || retryPolicy.Retryable(err)
+}
+
+/**
+Call attemptFunction until it succeeds, the policy gives up or ctx is done. Return the last attempt's result and error
+along with the errors of every failed attempt, in order
+ */
+func (retryPolicy *RetryPolicy) do(ctx context.Context, attemptFunction func(ctx context.Context) (interface{}, error)) (interface{}, []error, error) {
+    var attemptsErrors []error
+    for attempt := 1; ; attempt++ {
+        result, err := attemptFunction(context.WithValue(ctx, attemptContextKey{}, attempt))
+        if (err == nil) {
+            return result, attemptsErrors, nil
+        }
+        attemptsErrors = append(attemptsErrors, err)
+        if (ctx.Err() != nil || !retryPolicy.shouldRetry(attempt, err)) {
+            return result, attemptsErrors, err
+        }
+        timer := time.NewTimer(retryPolicy.delay(attempt + 1))
+        select {
+        case <-timer.C:
+        case <-ctx.Done():
+            timer.Stop()
+            return nil, attemptsErrors, ctx.Err()
+        }
+    }
+}
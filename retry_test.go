@@ -0,0 +1,136 @@
+package channeler
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "math"
+    "sync"
+    "testing"
+    "time"
+    "github.com/stretchr/testify/assert"
+)
+
+var errTransient = errors.New("transient failure")
+var errPermanent = errors.New("permanent failure")
+
+/**
+A flaky getRedApple succeeding on its 3rd attempt must not break getRedCherry, and its failed attempts must be recorded
+ */
+func TestChanneledCallback_RetryUntilSuccess(t *testing.T) {
+    channelerInstance := initFruitsChannelerWithStandardCbChain(t, timeDurationByFruitAndColor{
+        "apple": timeDurationByString{"yellow": 0, "red": 0, "green": 0},
+        "banana": timeDurationByString{"yellow": 0, "green": 0},
+        "cherry": timeDurationByString{"red": 0},
+    })
+    var seenAttempts []int
+    flakyRedApple := NewContextChanneledCallback(func(ctx context.Context, dependencies CallbackResults) (interface{}, error) {
+        seenAttempts = append(seenAttempts, AttemptFromContext(ctx))
+        if (AttemptFromContext(ctx) < 3) {
+            return nil, fmt.Errorf("attempt %d : %w", AttemptFromContext(ctx), errTransient)
+        }
+        return mapStringStringType{"fruit": "apple red", "time": "0"}, nil
+    }, []string{})
+    flakyRedApple.Retry = &RetryPolicy{MaxAttempts: 5, Delay: time.Millisecond}
+    (*channelerInstance.CallbackChain)["getRedApple"] = flakyRedApple
+
+    _, err := channelerInstance.Run()
+    assert.Nil(t, err)
+    assert.Equal(t, []int{1, 2, 3}, seenAttempts)
+    assert.Nil(t, channelerInstance.Errors["getRedApple"])
+    assert.Nil(t, channelerInstance.Errors["getRedCherry"])
+    assert.Len(t, channelerInstance.AttemptsErrors["getRedApple"], 2)
+    assert.True(t, errors.Is(channelerInstance.AttemptsErrors["getRedApple"][1], errTransient))
+    assert.NotContains(t, channelerInstance.AttemptsErrors, "getRedCherry")
+}
+
+/**
+Errors classified as not retryable, or the last allowed attempt's error, are stored and propagated
+ */
+func TestChanneledCallback_RetryGivesUp(t *testing.T) {
+    var attemptsNbMutex sync.Mutex
+    attemptsNb := map[string]int{}
+    failing := func(callbackName string, err error) *ChanneledCallback {
+        channeledCallback := NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            attemptsNbMutex.Lock()
+            defer attemptsNbMutex.Unlock()
+            attemptsNb[callbackName]++
+            return nil, err
+        }, []string{})
+        channeledCallback.Retry = &RetryPolicy{
+            MaxAttempts: 3,
+            Backoff: ExponentialBackoff,
+            Delay: time.Millisecond,
+            Jitter: 0.5,
+            Retryable: func(err error) bool {
+                return !errors.Is(err, errPermanent)
+            },
+        }
+        return channeledCallback
+    }
+    channelerInstance := NewChanneler(&CallbackChain{
+        "permanent": failing("permanent", errPermanent),
+        "transient": failing("transient", errTransient),
+        "dependent": NewChanneledCallback(noopCallback, []string{"transient"}),
+    })
+
+    _, err := channelerInstance.Run()
+    assert.True(t, errors.Is(err, errTransient))
+    assert.Equal(t, map[string]int{"permanent": 1, "transient": 3}, attemptsNb)
+    assert.Equal(t, errPermanent, channelerInstance.Errors["permanent"])
+    assert.Equal(t, errTransient, channelerInstance.Errors["transient"])
+    assert.True(t, errors.Is(channelerInstance.Errors["dependent"], errTransient))
+    assert.Len(t, channelerInstance.AttemptsErrors["transient"], 3)
+}
+
+/**
+Waiting between attempts stops as soon as the run is cancelled
+ */
+func TestChanneledCallback_RetryRespectsCancellation(t *testing.T) {
+    failing := NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+        return nil, errTransient
+    }, []string{})
+    failing.Retry = &RetryPolicy{MaxAttempts: 10, Delay: time.Hour}
+    channelerInstance := NewChanneler(&CallbackChain{"failing": failing})
+    channelerInstance.Timeout = 50 * time.Millisecond
+
+    start := time.Now()
+    _, err := channelerInstance.Run()
+    assert.True(t, errors.Is(err, context.DeadlineExceeded))
+    assert.Less(t, time.Since(start), time.Second)
+    var timeoutErr *TimeoutError
+    assert.True(t, errors.As(channelerInstance.Errors["failing"], &timeoutErr))
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+    constant := &RetryPolicy{Delay: time.Second}
+    assert.Equal(t, time.Second, constant.delay(2))
+    assert.Equal(t, time.Second, constant.delay(5))
+
+    exponential := &RetryPolicy{Backoff: ExponentialBackoff, Delay: time.Second, MaxDelay: 5 * time.Second}
+    assert.Equal(t, time.Second, exponential.delay(2))
+    assert.Equal(t, 2 * time.Second, exponential.delay(3))
+    assert.Equal(t, 4 * time.Second, exponential.delay(4))
+    assert.Equal(t, 5 * time.Second, exponential.delay(5))
+    assert.Equal(t, 5 * time.Second, exponential.delay(50))
+
+    unbounded := &RetryPolicy{Backoff: ExponentialBackoff, Delay: time.Second}
+    assert.Equal(t, 1 << 20 * time.Second, unbounded.delay(22))
+    for _, attempt := range []int{36, 64, 70, 1000} {
+        assert.Equal(t, time.Duration(math.MaxInt64), unbounded.delay(attempt), attempt)
+    }
+
+    overJittered := &RetryPolicy{Delay: time.Second, Jitter: 3}
+    underJittered := &RetryPolicy{Delay: time.Second, Jitter: -2}
+    for attempt := 2; attempt < 20; attempt++ {
+        assert.GreaterOrEqual(t, overJittered.delay(attempt), time.Duration(0))
+        assert.LessOrEqual(t, overJittered.delay(attempt), time.Second)
+        assert.Equal(t, time.Second, underJittered.delay(attempt))
+    }
+
+    jittered := &RetryPolicy{Delay: time.Second, Jitter: 0.5}
+    for attempt := 2; attempt < 20; attempt++ {
+        assert.GreaterOrEqual(t, jittered.delay(attempt), 500 * time.Millisecond)
+        assert.LessOrEqual(t, jittered.delay(attempt), time.Second)
+    }
+}
@@ -0,0 +1,69 @@
+package channeler
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "github.com/stretchr/testify/assert"
+)
+
+/**
+Run() returns a RunError listing every callback which did not succeed, root failures apart from propagated ones
+ */
+func TestChanneler_RunError(t *testing.T) {
+    channelerInstance := NewChanneler(&CallbackChain{
+        "ok": NewChanneledCallback(noopCallback, []string{}),
+        "failing": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return nil, errTransient
+        }, []string{}),
+        "panicking": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            panic(errPermanent)
+        }, []string{}),
+        "dependent": NewChanneledCallback(noopCallback, []string{"ok", "failing"}),
+    })
+
+    _, err := channelerInstance.Run()
+    var runErr *RunError
+    assert.True(t, errors.As(err, &runErr))
+    assert.Nil(t, runErr.Cause)
+    var failedNames []string
+    for _, failure := range runErr.Failures {
+        failedNames = append(failedNames, failure.CallbackName)
+    }
+    assert.Equal(t, []string{"dependent", "failing", "panicking"}, failedNames)
+    assert.Len(t, runErr.RootFailures(), 2)
+    assert.Equal(t, "failing", runErr.RootFailures()[0].CallbackName)
+    assert.Equal(t, "panicking", runErr.RootFailures()[1].CallbackName)
+    assert.Len(t, runErr.PropagatedFailures(), 1)
+    assert.Equal(t, UpstreamFailed, runErr.PropagatedFailures()[0].Status)
+
+    assert.True(t, errors.Is(err, errTransient))
+    assert.True(t, errors.Is(err, errPermanent))
+    var dependencyErr *DependencyError
+    assert.True(t, errors.As(err, &dependencyErr))
+    assert.Equal(t, "dependent upstream failed : " + channelerInstance.Errors["dependent"].Error() + "\n" +
+        "failing failed : " + errTransient.Error() + "\n" +
+        "panicking failed : " + channelerInstance.Errors["panicking"].Error(), err.Error())
+    //it can be joined with other errors like any other one
+    assert.True(t, errors.Is(errors.Join(errors.New("other"), err), errTransient))
+}
+
+/**
+A successful run returns a nil error, a stopped one a RunError holding the reason why it was stopped
+ */
+func TestChanneler_RunErrorCause(t *testing.T) {
+    channelerInstance := NewChanneler(&CallbackChain{
+        "ok": NewChanneledCallback(noopCallback, []string{}),
+    })
+    _, err := channelerInstance.Run()
+    assert.Nil(t, err)
+
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+    _, err = channelerInstance.RunContext(ctx)
+    var runErr *RunError
+    assert.True(t, errors.As(err, &runErr))
+    assert.Equal(t, context.Canceled, runErr.Cause)
+    assert.Empty(t, runErr.RootFailures())
+    assert.Len(t, runErr.PropagatedFailures(), 1)
+}
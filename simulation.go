@@ -0,0 +1,88 @@
+package channeler
+
+import (
+    "time"
+)
+
+//start of simulated runs : any non zero time would do
+var simulationStart = time.Unix(0, 0).UTC()
+
+/**
+Same as Plan.Simulate(), compiling the Channeler first
+ */
+func (channeler *Channeler) Simulate(durations map[string]time.Duration) (*RunReport, error) {
+    plan, err := channeler.Compile()
+    if (err != nil) {
+        return nil, err
+    }
+    return plan.Simulate(durations), nil
+}
+
+/**
+Predict how a run of the plan would go without running anything, given how long each callback function takes :
+callbacks missing from durations take no time. The scheduler is simulated along with the plan's MaxConcurrency and
+ReadyQueueOrder, every callback succeeding at its first attempt. The returned report tells when each callback would have
+been ready, started and finished, its Duration() being the expected duration of the run and its Timeline() the expected schedule
+ */
+func (plan *Plan) Simulate(durations map[string]time.Duration) *RunReport {
+    order := plan.readyQueueOrder
+    if (order == nil) {
+        order = FirstReadyFirst
+    }
+    outcomes := map[string]*NodeOutcome{}
+    pendingDependencies := map[string]int{}
+    var ready []*ReadyCallback
+    for _, callbackName := range plan.callbackNames {
+        outcomes[callbackName] = &NodeOutcome{CallbackName: callbackName, Status: Pending}
+        pendingDependencies[callbackName] = len(plan.nodes[callbackName].dependencies)
+        if (pendingDependencies[callbackName] == 0) {
+            ready = append(ready, &ReadyCallback{callbackName, simulationStart, len(plan.nodes[callbackName].dependants)})
+            outcomes[callbackName].ReadyAt = simulationStart
+        }
+    }
+    var running []string
+    now := simulationStart
+    for {
+        //hand out the free slots, the same way concurrencySlots.release() does
+        for len(ready) > 0 && (plan.maxConcurrency <= 0 || len(running) < plan.maxConcurrency) {
+            next := 0
+            for position := 1; position < len(ready); position++ {
+                if (order(ready[position], ready[next])) {
+                    next = position
+                }
+            }
+            outcome := outcomes[ready[next].CallbackName]
+            outcome.Status, outcome.StartedAt = Running, now
+            outcome.FinishedAt = now.Add(max(durations[outcome.CallbackName], 0))
+            running = append(running, outcome.CallbackName)
+            ready = append(ready[:next], ready[next+1:]...)
+        }
+        if (len(running) == 0) {
+            break
+        }
+        //move on to the next callbacks to finish, which make their dependants ready
+        now = outcomes[running[0]].FinishedAt
+        for _, callbackName := range running[1:] {
+            if (outcomes[callbackName].FinishedAt.Before(now)) {
+                now = outcomes[callbackName].FinishedAt
+            }
+        }
+        stillRunning := running[:0]
+        for _, callbackName := range running {
+            if (outcomes[callbackName].FinishedAt.After(now)) {
+                stillRunning = append(stillRunning, callbackName)
+                continue
+            }
+            outcomes[callbackName].Status, outcomes[callbackName].Attempts = Succeeded, 1
+            for _, dependantName := range plan.nodes[callbackName].dependants {
+                pendingDependencies[dependantName]--
+                if (pendingDependencies[dependantName] == 0) {
+                    ready = append(ready, &ReadyCallback{dependantName, now, len(plan.nodes[dependantName].dependants)})
+                    outcomes[dependantName].ReadyAt = now
+                }
+            }
+        }
+        running = stillRunning
+    }
+    return &RunReport{StartedAt: simulationStart, FinishedAt: now, Outcomes: outcomes}
+}
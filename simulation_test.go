@@ -0,0 +1,70 @@
+package channeler
+
+import (
+    "testing"
+    "time"
+    "github.com/stretchr/testify/assert"
+)
+
+var fruitsDurations = map[string]time.Duration{
+    "getRedApple": 1 * time.Second,
+    "getGreenApple": 6 * time.Second,
+    "getYellowApple": 3 * time.Second,
+    "getYellowBanana": 4 * time.Second,
+    "getGreenBanana": 5 * time.Second,
+    "getRedCherry": 6 * time.Second,
+}
+
+/**
+Same schedule as TestChanneler_RunAllOkIn11s, without waiting for it
+ */
+func TestPlan_Simulate(t *testing.T) {
+    report := fruitsPlan(t).Simulate(fruitsDurations)
+    assert.Equal(t, 11 * time.Second, report.Duration())
+    assert.True(t, report.Succeeded())
+    expected := "TIME            0           11s\n" +
+        "getGreenApple   |======..... succeeded\n" +
+        "getRedApple     |=.......... succeeded\n" +
+        "getYellowApple  |===........ succeeded\n" +
+        "getRedCherry    |-======.... succeeded\n" +
+        "getGreenBanana  |------===== succeeded\n" +
+        "getYellowBanana |------====. succeeded\n" +
+        "makeJam         |----------- succeeded\n"
+    assert.Equal(t, expected, report.Timeline().Gantt(11))
+    assert.Equal(t, []string{"getGreenApple", "getGreenBanana", "makeJam"}, fruitsPlan(t).AnalyzeRun(report).CriticalPath)
+}
+
+/**
+With a limited concurrency, ready callbacks queue up in the plan's ReadyQueueOrder
+ */
+func TestChanneler_SimulateMaxConcurrency(t *testing.T) {
+    channelerInstance := NewChanneler(&CallbackChain{
+        "getRedApple": NewChanneledCallback(noopCallback, []string{}),
+        "getGreenApple": NewChanneledCallback(noopCallback, []string{}),
+        "getYellowApple": NewChanneledCallback(noopCallback, []string{}),
+        "getYellowBanana": NewChanneledCallback(noopCallback, []string{"getGreenApple", "getYellowApple"}),
+        "getGreenBanana": NewChanneledCallback(noopCallback, []string{"getGreenApple", "getYellowApple"}),
+        "getRedCherry": NewChanneledCallback(noopCallback, []string{"getRedApple"}),
+    })
+    channelerInstance.MaxConcurrency = 2
+    channelerInstance.ReadyQueueOrder = MostDependantsFirst
+    report, err := channelerInstance.Simulate(fruitsDurations)
+    assert.Nil(t, err)
+    //the green and yellow apples go first as they unlock two bananas, then the red apple and the cherry
+    //share the slot left by the yellow apple, and the bananas run side by side once the green apple is there
+    startedAt := func(callbackName string) time.Duration {
+        return report.Outcomes[callbackName].StartedAt.Sub(report.StartedAt)
+    }
+    assert.Equal(t, 0 * time.Second, startedAt("getGreenApple"))
+    assert.Equal(t, 0 * time.Second, startedAt("getYellowApple"))
+    assert.Equal(t, 3 * time.Second, startedAt("getRedApple"))
+    assert.Equal(t, 4 * time.Second, startedAt("getRedCherry"))
+    assert.Equal(t, 10 * time.Second, startedAt("getYellowBanana"))
+    assert.Equal(t, 6 * time.Second, startedAt("getGreenBanana"))
+    assert.Equal(t, 3 * time.Second, report.Outcomes["getRedApple"].StartedAt.Sub(report.Outcomes["getRedApple"].ReadyAt))
+    assert.Equal(t, 14 * time.Second, report.Duration())
+
+    channelerInstance.CallbackChain = &CallbackChain{"loop": NewChanneledCallback(noopCallback, []string{"loop"})}
+    _, err = channelerInstance.Simulate(fruitsDurations)
+    assert.NotNil(t, err)
+}
@@ -0,0 +1,201 @@
+package channeler
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "sort"
+    "strings"
+    "time"
+)
+
+type IntervalKind int
+
+const (
+    //the callback waits for its dependencies or for a free slot
+    WaitInterval IntervalKind = iota
+    //the callback function is being invoked
+    RunInterval
+    //the callback is over while the run is not
+    IdleInterval
+)
+
+var intervalKindNames = []string{"waiting", "running", "idle"}
+
+//character drawing each kind of interval in Gantt charts
+var intervalKindMarks = []byte{'-', '=', '.'}
+
+func (kind IntervalKind) String() string {
+    if (kind < 0 || int(kind) >= len(intervalKindNames)) {
+        return "unknown"
+    }
+    return intervalKindNames[kind]
+}
+
+/**
+Part of the run spent by a callback in the same state, From and To being offsets from the start of the run
+ */
+type TimelineInterval struct {
+    Kind IntervalKind
+    From time.Duration
+    To   time.Duration
+}
+
+/**
+Consecutive intervals of a callback, covering the whole run
+ */
+type TimelineRow struct {
+    CallbackName string
+    Status       NodeStatus
+    Intervals    []TimelineInterval
+}
+
+/**
+Actual schedule of a run, one row per callback, sorted by the time their callback function was invoked
+ */
+type Timeline struct {
+    StartedAt time.Time
+    Duration  time.Duration
+    Rows      []TimelineRow
+}
+
+/**
+Return the timeline of the run
+ */
+func (report *RunReport) Timeline() *Timeline {
+    timeline := &Timeline{StartedAt: report.StartedAt, Duration: report.Duration()}
+    offset := func(at time.Time) time.Duration {
+        return min(max(at.Sub(report.StartedAt), 0), timeline.Duration)
+    }
+    for callbackName, outcome := range report.Outcomes {
+        row := TimelineRow{CallbackName: callbackName, Status: outcome.Status}
+        finishedAt := offset(outcome.FinishedAt)
+        waitedUntil := finishedAt
+        if (!outcome.StartedAt.IsZero()) {
+            waitedUntil = offset(outcome.StartedAt)
+        }
+        row.Intervals = appendInterval(row.Intervals, WaitInterval, 0, waitedUntil)
+        row.Intervals = appendInterval(row.Intervals, RunInterval, waitedUntil, finishedAt)
+        row.Intervals = appendInterval(row.Intervals, IdleInterval, finishedAt, timeline.Duration)
+        if (len(row.Intervals) == 0) {
+            row.Intervals = []TimelineInterval{{WaitInterval, 0, 0}}
+        }
+        timeline.Rows = append(timeline.Rows, row)
+    }
+    sort.Slice(timeline.Rows, func(i, j int) bool {
+        first, second := timeline.Rows[i].runFrom(), timeline.Rows[j].runFrom()
+        if (first != second) {
+            return first < second
+        }
+        return timeline.Rows[i].CallbackName < timeline.Rows[j].CallbackName
+    })
+    return timeline
+}
+
+/**
+Append the interval unless it is empty
+ */
+func appendInterval(intervals []TimelineInterval, kind IntervalKind, from time.Duration, to time.Duration) []TimelineInterval {
+    if (to <= from) {
+        return intervals
+    }
+    return append(intervals, TimelineInterval{kind, from, to})
+}
+
+/**
+Offset at which the callback function was invoked, or at which the callback ended if it never was
+ */
+func (row *TimelineRow) runFrom() time.Duration {
+    for _, interval := range row.Intervals {
+        if (interval.Kind != WaitInterval) {
+            return interval.From
+        }
+    }
+    return row.Intervals[len(row.Intervals) - 1].To
+}
+
+/**
+Event of the Chrome trace event format
+ */
+type chromeTraceEvent struct {
+    Name      string                 `json:"name"`
+    Category  string                 `json:"cat,omitempty"`
+    Phase     string                 `json:"ph"`
+    Timestamp float64                `json:"ts"`
+    Duration  float64                `json:"dur,omitempty"`
+    ProcessID int                    `json:"pid"`
+    ThreadID  int                    `json:"tid"`
+    Args      map[string]interface{} `json:"args,omitempty"`
+}
+
+/**
+Write the timeline in the Chrome trace event format, which chrome://tracing and Perfetto can open :
+each callback is a thread whose intervals are complete events, idle ones included
+ */
+func (timeline *Timeline) WriteChromeTrace(writer io.Writer) error {
+    microseconds := func(duration time.Duration) float64 {
+        return float64(duration) / float64(time.Microsecond)
+    }
+    events := []chromeTraceEvent{
+        {Name: "process_name", Phase: "M", ProcessID: 1, Args: map[string]interface{}{"name": "channeler run"}},
+    }
+    for position, row := range timeline.Rows {
+        threadID := position + 1
+        events = append(events,
+            chromeTraceEvent{Name: "thread_name", Phase: "M", ProcessID: 1, ThreadID: threadID, Args: map[string]interface{}{"name": row.CallbackName}},
+            chromeTraceEvent{Name: "thread_sort_index", Phase: "M", ProcessID: 1, ThreadID: threadID, Args: map[string]interface{}{"sort_index": position}},
+        )
+        for _, interval := range row.Intervals {
+            events = append(events, chromeTraceEvent{
+                Name: interval.Kind.String(),
+                Category: "channeler",
+                Phase: "X",
+                Timestamp: microseconds(interval.From),
+                Duration: microseconds(interval.To - interval.From),
+                ProcessID: 1,
+                ThreadID: threadID,
+                Args: map[string]interface{}{"callback": row.CallbackName, "status": row.Status.String()},
+            })
+        }
+    }
+    return json.NewEncoder(writer).Encode(map[string]interface{}{"traceEvents": events, "displayTimeUnit": "ms"})
+}
+
+/**
+Render the timeline as an ASCII Gantt chart of the given width in characters, not counting the callback names and
+statuses : waiting is drawn with '-', running with '=' and idle with '.'
+ */
+func (timeline *Timeline) Gantt(width int) string {
+    width = max(width, 1)
+    nameWidth := len("TIME")
+    for _, row := range timeline.Rows {
+        nameWidth = max(nameWidth, len(row.CallbackName))
+    }
+    var builder strings.Builder
+    fmt.Fprintf(&builder, "%-*s 0%s%s\n", nameWidth, "TIME", strings.Repeat(" ", width), timeline.Duration)
+    for _, row := range timeline.Rows {
+        bars := []byte(strings.Repeat(" ", width))
+        for _, interval := range row.Intervals {
+            from, to := timeline.column(interval.From, width), timeline.column(interval.To, width)
+            //an interval shorter than a column is still drawn when its column is blank
+            if (to == from && interval.To > interval.From && from < width && bars[from] == ' ') {
+                to++
+            }
+            for column := from; column < to; column++ {
+                bars[column] = intervalKindMarks[interval.Kind]
+            }
+        }
+        fmt.Fprintf(&builder, "%-*s |%s %s\n", nameWidth, row.CallbackName, bars, row.Status)
+    }
+    return builder.String()
+}
+
+/**
+Column of a Gantt chart of the given width where the given offset falls, rounded to the nearest one
+ */
+func (timeline *Timeline) column(offset time.Duration, width int) int {
+    if (timeline.Duration <= 0) {
+        return 0
+    }
+    return int((int64(offset) * int64(width) + int64(timeline.Duration) / 2) / int64(timeline.Duration))
+}
@@ -0,0 +1,117 @@
+package channeler
+
+import (
+    "bytes"
+    "encoding/json"
+    "strings"
+    "testing"
+    "time"
+    "github.com/stretchr/testify/assert"
+)
+
+/**
+Report of the run of TestChanneler_RunAllOkIn11s, the bananas waiting for the green and yellow apples,
+the cherry for the red apple, the green banana failing
+ */
+func fruitsReport() *RunReport {
+    startedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    at := func(seconds int) time.Time {
+        return startedAt.Add(time.Duration(seconds) * time.Second)
+    }
+    outcome := func(callbackName string, status NodeStatus, startedAt int, finishedAt int) *NodeOutcome {
+        return &NodeOutcome{CallbackName: callbackName, Status: status, ReadyAt: at(startedAt), StartedAt: at(startedAt), FinishedAt: at(finishedAt)}
+    }
+    return &RunReport{startedAt, at(11), map[string]*NodeOutcome{
+        "getRedApple": outcome("getRedApple", Succeeded, 0, 1),
+        "getGreenApple": outcome("getGreenApple", Succeeded, 0, 6),
+        "getYellowApple": outcome("getYellowApple", Succeeded, 0, 3),
+        "getYellowBanana": outcome("getYellowBanana", Succeeded, 6, 10),
+        "getGreenBanana": outcome("getGreenBanana", Failed, 6, 11),
+        "getRedCherry": outcome("getRedCherry", Succeeded, 1, 7),
+        "makeJam": {CallbackName: "makeJam", Status: UpstreamFailed, FinishedAt: at(11)},
+    }}
+}
+
+func TestRunReport_Timeline(t *testing.T) {
+    timeline := fruitsReport().Timeline()
+    assert.Equal(t, 11 * time.Second, timeline.Duration)
+    var callbackNames []string
+    for _, row := range timeline.Rows {
+        callbackNames = append(callbackNames, row.CallbackName)
+    }
+    assert.Equal(t, []string{"getGreenApple", "getRedApple", "getYellowApple", "getRedCherry", "getGreenBanana", "getYellowBanana", "makeJam"}, callbackNames)
+    assert.Equal(t, []TimelineInterval{
+        {WaitInterval, 0, 6 * time.Second},
+        {RunInterval, 6 * time.Second, 10 * time.Second},
+        {IdleInterval, 10 * time.Second, 11 * time.Second},
+    }, timeline.Rows[5].Intervals)
+    assert.Equal(t, []TimelineInterval{{WaitInterval, 0, 11 * time.Second}}, timeline.Rows[6].Intervals)
+}
+
+func TestTimeline_Gantt(t *testing.T) {
+    expected := "TIME            0           11s\n" +
+        "getGreenApple   |======..... succeeded\n" +
+        "getRedApple     |=.......... succeeded\n" +
+        "getYellowApple  |===........ succeeded\n" +
+        "getRedCherry    |-======.... succeeded\n" +
+        "getGreenBanana  |------===== failed\n" +
+        "getYellowBanana |------====. succeeded\n" +
+        "makeJam         |----------- upstream failed\n"
+    assert.Equal(t, expected, fruitsReport().Timeline().Gantt(11))
+}
+
+func TestTimeline_WriteChromeTrace(t *testing.T) {
+    var buffer bytes.Buffer
+    assert.Nil(t, fruitsReport().Timeline().WriteChromeTrace(&buffer))
+    var trace struct {
+        TraceEvents []chromeTraceEvent `json:"traceEvents"`
+    }
+    assert.Nil(t, json.Unmarshal(buffer.Bytes(), &trace))
+
+    threadNames := map[int]string{}
+    intervals := map[string][]string{}
+    for _, event := range trace.TraceEvents {
+        switch {
+        case event.Phase == "M" && event.Name == "thread_name":
+            threadNames[event.ThreadID] = event.Args["name"].(string)
+        case event.Phase == "X":
+            callbackName := threadNames[event.ThreadID]
+            assert.Equal(t, callbackName, event.Args["callback"])
+            intervals[callbackName] = append(intervals[callbackName], event.Name)
+            if (callbackName == "getRedCherry" && event.Name == "running") {
+                assert.Equal(t, 1e6, event.Timestamp)
+                assert.Equal(t, 6e6, event.Duration)
+            }
+        }
+    }
+    assert.Len(t, threadNames, 7)
+    assert.Equal(t, []string{"waiting", "running", "idle"}, intervals["getRedCherry"])
+    assert.Equal(t, []string{"running", "idle"}, intervals["getRedApple"])
+    assert.Equal(t, []string{"waiting"}, intervals["makeJam"])
+}
+
+/**
+The timeline of an actual run shows when each callback waited and ran
+ */
+func TestChanneler_RunTimeline(t *testing.T) {
+    channelerInstance := NewChanneler(&CallbackChain{
+        "slow": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            time.Sleep(30 * time.Millisecond)
+            return nil, nil
+        }, []string{}),
+        "dependent": NewChanneledCallback(noopCallback, []string{"slow"}),
+    })
+    report, err := channelerInstance.Run()
+    assert.Nil(t, err)
+    timeline := report.Timeline()
+    assert.Equal(t, []string{"slow", "dependent"}, []string{timeline.Rows[0].CallbackName, timeline.Rows[1].CallbackName})
+    assert.Equal(t, WaitInterval, timeline.Rows[1].Intervals[0].Kind)
+    assert.GreaterOrEqual(t, timeline.Rows[1].Intervals[0].To, 30 * time.Millisecond)
+    //slow runs from the start while dependent waits for it, then is invoked right before the run ends
+    rows := strings.Split(timeline.Gantt(40), "\n")
+    assert.Len(t, rows, 4)
+    assert.Regexp(t, `^slow      \|-{0,2}={36,40}\.{0,2} succeeded$`, rows[1])
+    assert.Regexp(t, `^dependent \|-{36,40}[=.]{0,4} succeeded$`, rows[2])
+    assert.Len(t, rows[1], len("slow      |") + 40 + len(" succeeded"))
+    assert.Len(t, rows[2], len(rows[1]))
+}
@@ -0,0 +1,120 @@
+package channeler
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "time"
+    "github.com/stretchr/testify/assert"
+)
+
+/**
+A callback exceeding its own Timeout gets a TimeoutError which propagates to its dependents,
+even though it ignores its context and keeps sleeping
+ */
+func TestChanneledCallback_Timeout(t *testing.T) {
+    hung := NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+        time.Sleep(2 * time.Second)
+        return "too late", nil
+    }, []string{})
+    hung.Timeout = 50 * time.Millisecond
+    channelerInstance := NewChanneler(&CallbackChain{
+        "hung": hung,
+        "dependent": NewChanneledCallback(noopCallback, []string{"hung"}),
+        "independent": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return "ok", nil
+        }, []string{}),
+    })
+
+    start := time.Now()
+    _, err := channelerInstance.Run()
+    assert.True(t, errors.Is(err, context.DeadlineExceeded))
+    assert.Less(t, time.Since(start), time.Second)
+
+    var timeoutErr *TimeoutError
+    assert.True(t, errors.As(channelerInstance.Errors["hung"], &timeoutErr))
+    assert.Equal(t, "hung", timeoutErr.CallbackName)
+    assert.GreaterOrEqual(t, timeoutErr.Elapsed, 50 * time.Millisecond)
+    assert.True(t, errors.Is(timeoutErr, context.DeadlineExceeded))
+    assert.True(t, errors.Is(channelerInstance.Errors["dependent"], channelerInstance.Errors["hung"]))
+    assert.Equal(t, "ok", channelerInstance.Results["independent"])
+}
+
+/**
+The callback's context is cancelled by its Timeout, so a well behaved callback stops on its own
+ */
+func TestChanneledCallback_TimeoutCancelsContext(t *testing.T) {
+    cancelled := make(chan struct{})
+    polite := NewContextChanneledCallback(func(ctx context.Context, dependencies CallbackResults) (interface{}, error) {
+        <-ctx.Done()
+        close(cancelled)
+        return nil, ctx.Err()
+    }, []string{})
+    polite.Timeout = 20 * time.Millisecond
+    channelerInstance := NewChanneler(&CallbackChain{"polite": polite})
+
+    _, err := channelerInstance.Run()
+    assert.True(t, errors.Is(err, context.DeadlineExceeded))
+    var timeoutErr *TimeoutError
+    assert.True(t, errors.As(channelerInstance.Errors["polite"], &timeoutErr))
+    select {
+    case <-cancelled:
+    case <-time.After(time.Second):
+        t.Fatal("the callback's context was not cancelled")
+    }
+}
+
+/**
+Run must return by the Channeler's deadline, marking callbacks which did not finish as timed out
+ */
+func TestChanneler_RunTimeout(t *testing.T) {
+    channelerInstance := NewChanneler(&CallbackChain{
+        "fast": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return "fast", nil
+        }, []string{}),
+        "stubborn": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            time.Sleep(2 * time.Second)
+            return "too late", nil
+        }, []string{}),
+        "dependent": NewChanneledCallback(noopCallback, []string{"stubborn"}),
+    })
+    channelerInstance.Timeout = 100 * time.Millisecond
+
+    start := time.Now()
+    _, err := channelerInstance.Run()
+    assert.True(t, errors.Is(err, context.DeadlineExceeded))
+    assert.Less(t, time.Since(start), time.Second)
+
+    assert.Equal(t, "fast", channelerInstance.Results["fast"])
+    for _, callbackName := range []string{"stubborn", "dependent"} {
+        var timeoutErr *TimeoutError
+        assert.True(t, errors.As(channelerInstance.Errors[callbackName], &timeoutErr), callbackName)
+        assert.Equal(t, callbackName, timeoutErr.CallbackName)
+        assert.GreaterOrEqual(t, timeoutErr.Elapsed, 100 * time.Millisecond)
+    }
+}
+
+/**
+A callback returning the error of a nested Channeler which timed out failed, it did not time out itself
+ */
+func TestChanneler_RunNestedTimeout(t *testing.T) {
+    channelerInstance := NewChanneler(&CallbackChain{
+        "getFruits": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            fruitsChanneler := NewChanneler(&CallbackChain{
+                "getApple": NewContextChanneledCallback(func(ctx context.Context, dependencies CallbackResults) (interface{}, error) {
+                    <-ctx.Done()
+                    return nil, ctx.Err()
+                }, []string{}),
+            })
+            fruitsChanneler.Timeout = 10 * time.Millisecond
+            _, err := fruitsChanneler.Run()
+            return nil, err
+        }, []string{}),
+    })
+
+    report, err := channelerInstance.Run()
+    var timeoutErr *TimeoutError
+    assert.True(t, errors.As(err, &timeoutErr))
+    assert.Equal(t, "getApple", timeoutErr.CallbackName)
+    assert.Equal(t, Failed, report.Outcomes["getFruits"].Status)
+}
@@ -0,0 +1,164 @@
+package channeler
+
+import (
+    "context"
+    "sync"
+    "time"
+)
+
+/**
+Key and value of an attribute of a Span, the value being a string, a bool, an int, an int64, a float64 or a []string
+ */
+type Attribute struct {
+    Key   string
+    Value interface{}
+}
+
+/**
+Starts the spans of the runs of a Channeler : one span per run, parent of one span per callback.
+The context returned by Start carries the span so that spans started from it, e.g. by a Channeler ran with
+RunContext() from inside a callback function, become its children
+ */
+type Tracer interface {
+    Start(ctx context.Context, spanName string, attributes ...Attribute) (context.Context, Span)
+}
+
+/**
+A timed operation of a trace, ended once
+ */
+type Span interface {
+    SetAttributes(attributes ...Attribute)
+    RecordError(err error)
+    End()
+}
+
+/**
+Used when the Channeler has no Tracer
+ */
+type noopTracer struct{}
+type noopSpan struct{}
+
+func (tracer noopTracer) Start(ctx context.Context, spanName string, attributes ...Attribute) (context.Context, Span) {
+    return ctx, noopSpan{}
+}
+func (span noopSpan) SetAttributes(attributes ...Attribute) {}
+func (span noopSpan) RecordError(err error) {}
+func (span noopSpan) End() {}
+
+/**
+Attributes describing how a callback ended, the time it waited for its dependencies and a free slot being told
+apart from the time spent invoking its callback function
+ */
+func outcomeAttributes(outcome *NodeOutcome, runStartedAt time.Time) []Attribute {
+    waitedUntil := outcome.StartedAt
+    if (waitedUntil.IsZero()) {
+        waitedUntil = outcome.FinishedAt
+    }
+    attributes := []Attribute{
+        {"channeler.status", outcome.Status.String()},
+        {"channeler.attempts", outcome.Attempts},
+        {"channeler.wait_ms", milliseconds(waitedUntil.Sub(runStartedAt))},
+        {"channeler.exec_ms", milliseconds(outcome.Duration())},
+    }
+    if (!outcome.ReadyAt.IsZero() && !outcome.StartedAt.IsZero()) {
+        attributes = append(attributes, Attribute{"channeler.queue_ms", milliseconds(outcome.StartedAt.Sub(outcome.ReadyAt))})
+    }
+    if (len(outcome.FailedDependencies) > 0) {
+        attributes = append(attributes, Attribute{"channeler.failed_dependencies", outcome.FailedDependencies})
+    }
+    return attributes
+}
+
+func milliseconds(duration time.Duration) float64 {
+    return float64(duration) / float64(time.Millisecond)
+}
+
+/**
+Tracer keeping its spans in memory, e.g. to check the spans of a run in tests
+ */
+type MemoryTracer struct {
+    mutex sync.Mutex
+    spans []*memorySpan
+}
+
+/**
+Copy of a span recorded by a MemoryTracer
+ */
+type RecordedSpan struct {
+    //position of the span in MemoryTracer.Spans(), starting at 1
+    ID         int
+    //ID of the span carried by the context the span was started from, 0 for a root span
+    ParentID   int
+    Name       string
+    Attributes map[string]interface{}
+    Errors     []error
+    StartedAt  time.Time
+    //zero as long as the span is not ended
+    EndedAt    time.Time
+}
+
+type memorySpan struct {
+    tracer   *MemoryTracer
+    recorded RecordedSpan
+}
+
+type memorySpanKey struct{}
+
+func NewMemoryTracer() *MemoryTracer {
+    return &MemoryTracer{}
+}
+
+func (tracer *MemoryTracer) Start(ctx context.Context, spanName string, attributes ...Attribute) (context.Context, Span) {
+    tracer.mutex.Lock()
+    defer tracer.mutex.Unlock()
+    span := &memorySpan{tracer, RecordedSpan{ID: len(tracer.spans) + 1, Name: spanName, Attributes: map[string]interface{}{}, StartedAt: time.Now()}}
+    if parent, ok := ctx.Value(memorySpanKey{}).(*memorySpan); ok && parent.tracer == tracer {
+        span.recorded.ParentID = parent.recorded.ID
+    }
+    for _, attribute := range attributes {
+        span.recorded.Attributes[attribute.Key] = attribute.Value
+    }
+    tracer.spans = append(tracer.spans, span)
+    return context.WithValue(ctx, memorySpanKey{}, span), span
+}
+
+/**
+Return a copy of every span started so far, in the order they were started
+ */
+func (tracer *MemoryTracer) Spans() []RecordedSpan {
+    tracer.mutex.Lock()
+    defer tracer.mutex.Unlock()
+    spans := make([]RecordedSpan, 0, len(tracer.spans))
+    for _, span := range tracer.spans {
+        recorded := span.recorded
+        recorded.Attributes = map[string]interface{}{}
+        for key, value := range span.recorded.Attributes {
+            recorded.Attributes[key] = value
+        }
+        recorded.Errors = append([]error{}, span.recorded.Errors...)
+        spans = append(spans, recorded)
+    }
+    return spans
+}
+
+func (span *memorySpan) SetAttributes(attributes ...Attribute) {
+    span.tracer.mutex.Lock()
+    defer span.tracer.mutex.Unlock()
+    for _, attribute := range attributes {
+        span.recorded.Attributes[attribute.Key] = attribute.Value
+    }
+}
+
+func (span *memorySpan) RecordError(err error) {
+    span.tracer.mutex.Lock()
+    defer span.tracer.mutex.Unlock()
+    span.recorded.Errors = append(span.recorded.Errors, err)
+}
+
+func (span *memorySpan) End() {
+    span.tracer.mutex.Lock()
+    defer span.tracer.mutex.Unlock()
+    if (span.recorded.EndedAt.IsZero()) {
+        span.recorded.EndedAt = time.Now()
+    }
+}
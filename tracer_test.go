@@ -0,0 +1,68 @@
+package channeler
+
+import (
+    "context"
+    "testing"
+    "time"
+    "github.com/stretchr/testify/assert"
+)
+
+/**
+A run gets a span parent of one span per callback, and a Channeler ran from inside a callback function with
+the context it is given gets its spans nested under the span of that callback
+ */
+func TestChanneler_RunTracer(t *testing.T) {
+    tracer := NewMemoryTracer()
+    channelerInstance := NewChanneler(&CallbackChain{
+        "getFruits": NewContextChanneledCallback(func(ctx context.Context, dependencies CallbackResults) (interface{}, error) {
+            fruitsChanneler := NewChanneler(&CallbackChain{
+                "getApple": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+                    time.Sleep(20 * time.Millisecond)
+                    return "apple", nil
+                }, []string{}),
+            })
+            fruitsChanneler.Tracer = tracer
+            _, err := fruitsChanneler.RunContext(ctx)
+            return fruitsChanneler.Results, err
+        }, []string{}),
+        "getBook": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return nil, errTransient
+        }, []string{}),
+        "makeJam": NewChanneledCallback(noopCallback, []string{"getFruits", "getBook"}),
+    })
+    channelerInstance.Tracer = tracer
+    channelerInstance.Run()
+
+    spans := map[string]RecordedSpan{}
+    var runSpans []RecordedSpan
+    for _, span := range tracer.Spans() {
+        assert.False(t, span.EndedAt.IsZero(), span.Name)
+        if (span.Name == "channeler.run") {
+            runSpans = append(runSpans, span)
+        } else {
+            spans[span.Name] = span
+        }
+    }
+    assert.Len(t, runSpans, 2)
+    outerRun, innerRun := runSpans[0], runSpans[1]
+    assert.Equal(t, 0, outerRun.ParentID)
+    assert.Equal(t, false, outerRun.Attributes["channeler.succeeded"])
+    assert.Len(t, outerRun.Errors, 1)
+    for _, callbackName := range []string{"getFruits", "getBook", "makeJam"} {
+        assert.Equal(t, outerRun.ID, spans[callbackName].ParentID, callbackName)
+        assert.Equal(t, callbackName, spans[callbackName].Attributes["channeler.callback"])
+    }
+    assert.Equal(t, spans["getFruits"].ID, innerRun.ParentID)
+    assert.Equal(t, innerRun.ID, spans["getApple"].ParentID)
+    assert.Equal(t, true, innerRun.Attributes["channeler.succeeded"])
+
+    assert.Equal(t, "succeeded", spans["getApple"].Attributes["channeler.status"])
+    assert.GreaterOrEqual(t, spans["getApple"].Attributes["channeler.exec_ms"].(float64), 20.0)
+    //getFruits runs as long as the nested run
+    assert.GreaterOrEqual(t, spans["getFruits"].Attributes["channeler.exec_ms"].(float64), 20.0)
+    assert.Less(t, spans["getFruits"].Attributes["channeler.wait_ms"].(float64), 20.0)
+    assert.Equal(t, 0.0, spans["makeJam"].Attributes["channeler.exec_ms"])
+    assert.Equal(t, "upstream failed", spans["makeJam"].Attributes["channeler.status"])
+    assert.Equal(t, []string{"getBook"}, spans["makeJam"].Attributes["channeler.failed_dependencies"])
+    assert.Equal(t, []error{errTransient}, spans["getBook"].Errors)
+}
@@ -0,0 +1,108 @@
+package channeler
+
+import (
+    "context"
+)
+
+/**
+Anything designating a callback of a CallbackChain by its name, such as a Node
+ */
+type NodeRef interface {
+    Name() string
+}
+
+/**
+Wrap a dependency given to Add() so that the callback still runs when it fails, see DependencyOptions.Optional
+ */
+func Optional(dependency NodeRef) NodeRef {
+    return withDependencyOptions(dependency, func(dependencyOptions *DependencyOptions) {
+        dependencyOptions.Optional = true
+    })
+}
+
+/**
+Wrap a dependency given to Add() so that the callback consumes its partial result when it fails, see DependencyOptions.AcceptPartial
+ */
+func AcceptPartial(dependency NodeRef) NodeRef {
+    return withDependencyOptions(dependency, func(dependencyOptions *DependencyOptions) {
+        dependencyOptions.AcceptPartial = true
+    })
+}
+
+type dependencyRef struct {
+    NodeRef
+    options DependencyOptions
+}
+
+func withDependencyOptions(dependency NodeRef, setOption func(dependencyOptions *DependencyOptions)) NodeRef {
+    ref, isWrapped := dependency.(dependencyRef)
+    if (!isWrapped) {
+        ref = dependencyRef{NodeRef: dependency}
+    }
+    setOption(&ref.options)
+    return ref
+}
+
+/**
+Typed handle on a callback of a CallbackChain whose result is of type T, as returned by Add().
+It lets Get() fetch the callback's result without any type assertion on the caller side
+ */
+type Node[T any] struct {
+    name string
+}
+
+func (node Node[T]) Name() string {
+    return node.name
+}
+
+/**
+Return a typed handle on a callback which has been registered without Add(), e.g. directly in a CallbackChain
+ */
+func NodeNamed[T any](name string) Node[T] {
+    return Node[T]{name}
+}
+
+/**
+Register a typed callback function under the given name in the Channeler's CallbackChain, depending on the given nodes,
+and return its handle. The callback is a regular ChanneledCallback which can be tuned (Timeout, Retry...) through the CallbackChain
+ */
+func Add[T any](channeler *Channeler, name string, callbackFunction func(ctx context.Context, dependencies CallbackResults) (T, error), dependencies ...NodeRef) Node[T] {
+    dependenciesNames := make([]string, 0, len(dependencies))
+    dependenciesOptions := map[string]DependencyOptions{}
+    for _, dependency := range dependencies {
+        dependenciesNames = append(dependenciesNames, dependency.Name())
+        if ref, isWrapped := dependency.(dependencyRef); isWrapped {
+            dependenciesOptions[dependency.Name()] = ref.options
+        }
+    }
+    if (channeler.CallbackChain == nil) {
+        channeler.CallbackChain = &CallbackChain{}
+    }
+    channeledCallback := NewContextChanneledCallback(func(ctx context.Context, dependencies CallbackResults) (interface{}, error) {
+        result, err := callbackFunction(ctx, dependencies)
+        //a zero value returned along with an error is no partial result, see isPartialResult()
+        if (err != nil && !isPartialResult(result)) {
+            return nil, err
+        }
+        return result, err
+    }, dependenciesNames)
+    if (len(dependenciesOptions) > 0) {
+        channeledCallback.DependenciesOptions = dependenciesOptions
+    }
+    (*channeler.CallbackChain)[name] = channeledCallback
+    return Node[T]{name}
+}
+
+/**
+Fetch the result of a node from a Channeler's Results or from the dependencies received by a callback.
+The boolean is false when there is no such result (missing, failed callback...) or when it is not of type T.
+The value of an accepted PartialResult is returned as is, see CallbackResults.Err() to know about its error
+ */
+func Get[T any](results CallbackResults, node Node[T]) (T, bool) {
+    value := results[node.name]
+    if partialResult, isPartial := value.(PartialResult); isPartial {
+        value = partialResult.Value
+    }
+    result, isOfTypeT := value.(T)
+    return result, isOfTypeT
+}
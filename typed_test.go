@@ -0,0 +1,70 @@
+package channeler
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "github.com/stretchr/testify/assert"
+)
+
+type fruit struct {
+    Name  string
+    Color string
+}
+
+/**
+Typed nodes interoperate with the untyped ChanneledCallback of the same chain, without any type assertion
+ */
+func TestAdd_TypedNodes(t *testing.T) {
+    channelerInstance := NewChanneler(&CallbackChain{
+        "getBasket": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return "wicker basket", nil
+        }, []string{}),
+    })
+    basket := NodeNamed[string]("getBasket")
+    redApple := Add(channelerInstance, "getRedApple", func(ctx context.Context, dependencies CallbackResults) (fruit, error) {
+        return fruit{"apple", "red"}, nil
+    })
+    redCherry := Add(channelerInstance, "getRedCherry", func(ctx context.Context, dependencies CallbackResults) (fruit, error) {
+        apple, isset := Get(dependencies, redApple)
+        assert.True(t, isset)
+        return fruit{"cherry", apple.Color}, nil
+    }, redApple)
+    fruitsNb := Add(channelerInstance, "fillBasket", func(ctx context.Context, dependencies CallbackResults) (int, error) {
+        cherry, _ := Get(dependencies, redCherry)
+        basketName, _ := Get(dependencies, basket)
+        assert.Equal(t, "wicker basket", basketName)
+        assert.Equal(t, "cherry", cherry.Name)
+        return len(dependencies), nil
+    }, redCherry, basket)
+
+    _, err := channelerInstance.Run()
+    assert.Nil(t, err)
+    cherry, isset := Get(channelerInstance.Results, redCherry)
+    assert.True(t, isset)
+    assert.Equal(t, fruit{"cherry", "red"}, cherry)
+    count, isset := Get(channelerInstance.Results, fruitsNb)
+    assert.True(t, isset)
+    assert.Equal(t, 2, count)
+}
+
+/**
+Getting the result of a failed node, or with a handle of the wrong type, reports it instead of panicking
+ */
+func TestGet_MissingOrMistyped(t *testing.T) {
+    channelerInstance := NewChanneler(nil)
+    failing := Add(channelerInstance, "failing", func(ctx context.Context, dependencies CallbackResults) (string, error) {
+        return "", errTransient
+    })
+    succeeding := Add(channelerInstance, "succeeding", func(ctx context.Context, dependencies CallbackResults) (string, error) {
+        return "ok", nil
+    })
+
+    _, err := channelerInstance.Run()
+    assert.True(t, errors.Is(err, errTransient))
+    assert.True(t, errors.Is(channelerInstance.Errors["failing"], errTransient))
+    _, isset := Get(channelerInstance.Results, failing)
+    assert.False(t, isset)
+    _, isset = Get(channelerInstance.Results, NodeNamed[int](succeeding.Name()))
+    assert.False(t, isset)
+}
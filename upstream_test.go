@@ -0,0 +1,105 @@
+package channeler
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "time"
+    "github.com/stretchr/testify/assert"
+)
+
+/**
+A dependent callback, and everything downstream of it, must learn about a failing dependency as soon as it fails
+instead of waiting for its slower dependencies
+ */
+func TestChanneler_RunShortCircuitsOnFailedDependency(t *testing.T) {
+    channelerInstance := NewChanneler(&CallbackChain{
+        "slow": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            time.Sleep(300 * time.Millisecond)
+            return "slow", nil
+        }, []string{}),
+        "failing": NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+            return nil, errPermanent
+        }, []string{}),
+        "dependent": NewChanneledCallback(noopCallback, []string{"slow", "failing"}),
+        "downstream": NewChanneledCallback(noopCallback, []string{"dependent"}),
+    })
+
+    report, err := channelerInstance.Run()
+    assert.True(t, errors.Is(err, errPermanent))
+    assert.Equal(t, "slow", channelerInstance.Results["slow"])
+    for _, callbackName := range []string{"dependent", "downstream"} {
+        outcome := report.Outcomes[callbackName]
+        assert.Equal(t, UpstreamFailed, outcome.Status, callbackName)
+        assert.True(t, errors.Is(outcome.Err, errPermanent), callbackName)
+        assert.Less(t, outcome.FinishedAt.Sub(report.StartedAt), 200 * time.Millisecond, callbackName)
+    }
+    assert.Equal(t, []string{"failing"}, report.Outcomes["dependent"].FailedDependencies)
+    assert.Equal(t, []string{"dependent"}, report.Outcomes["downstream"].FailedDependencies)
+}
+
+/**
+Every required dependency already known to have failed is reported, not only the first one received
+ */
+func TestChanneledCallback_AwaitDependenciesReportsEveryFailure(t *testing.T) {
+    errOther := errors.New("other")
+    channeledCallback := NewChanneledCallback(noopCallback, []string{"a", "b", "c", "d"}).WithOptionalDependencies("c")
+    inbox := make(envelopeChannel, 4)
+    inbox <- envelope{"b", nil, errPermanent}
+    inbox <- envelope{"c", nil, errTransient}
+    inbox <- envelope{"a", nil, errOther}
+
+    dependenciesResults, failedDependencies := channeledCallback.awaitDependencies(context.Background(), inbox)
+    assert.Equal(t, map[string]error{"a": errOther, "b": errPermanent}, failedDependencies)
+    assert.Equal(t, errTransient, dependenciesResults.Err("c"))
+}
+
+/**
+Dependents get a DependencyError holding the path from the callback which failed on its own, whose cause can be found
+with errors.Is and errors.As
+ */
+func TestChanneler_RunDependencyErrorPath(t *testing.T) {
+    channelerInstance := initFruitsChannelerWithStandardCbChain(t, timeDurationByFruitAndColor{
+        "apple": timeDurationByString{"yellow": 0, "red": 0, "green": 0},
+        "banana": timeDurationByString{"yellow": 0, "green": 0},
+        "cherry": timeDurationByString{"red": 0},
+    })
+    (*channelerInstance.CallbackChain)["getYellowApple"] = NewChanneledCallback(func(dependencies CallbackResults) (interface{}, error) {
+        return nil, &PanicError{CallbackName: "getYellowApple", Value: errPermanent}
+    }, []string{})
+    (*channelerInstance.CallbackChain)["peelGreenBanana"] = NewChanneledCallback(noopCallback, []string{"getGreenBanana"})
+
+    report, err := channelerInstance.Run()
+    assert.True(t, errors.Is(err, errPermanent))
+    var dependencyErr *DependencyError
+    assert.True(t, errors.As(channelerInstance.Errors["peelGreenBanana"], &dependencyErr))
+    assert.Equal(t, "peelGreenBanana", dependencyErr.CallbackName)
+    assert.Equal(t, []string{"getGreenBanana"}, dependencyErr.FailedDependencies)
+    assert.Equal(t, []string{"getYellowApple", "getGreenBanana", "peelGreenBanana"}, dependencyErr.Path)
+    assert.Equal(t, channelerInstance.Errors["getYellowApple"], dependencyErr.Cause)
+    assert.Equal(t, []string{"getYellowApple"}, dependencyErr.RootCauses())
+    assert.Equal(t, "peelGreenBanana could not run because getGreenBanana failed (getYellowApple -> getGreenBanana -> peelGreenBanana) : " +
+        channelerInstance.Errors["getYellowApple"].Error(), dependencyErr.Error())
+
+    var panicErr *PanicError
+    assert.True(t, errors.As(dependencyErr, &panicErr))
+    assert.True(t, errors.Is(dependencyErr, errPermanent))
+    assert.Equal(t, []string{"getYellowApple"}, report.RootCauses())
+}
+
+/**
+When several dependencies failed, each of them and each of their root causes can be found
+ */
+func TestUpstreamFailure_SeveralDependencies(t *testing.T) {
+    errOther := errors.New("other")
+    upstreamErr := upstreamFailure("b", map[string]error{"a": errOther})
+    dependencyErr := upstreamFailure("d", map[string]error{"c": errPermanent, "b": upstreamErr})
+
+    assert.Equal(t, []string{"b", "c"}, dependencyErr.FailedDependencies)
+    assert.Equal(t, []error{upstreamErr, errPermanent}, dependencyErr.Errs)
+    assert.Equal(t, []string{"a", "b", "d"}, dependencyErr.Path)
+    assert.Equal(t, errOther, dependencyErr.Cause)
+    assert.Equal(t, []string{"a", "c"}, dependencyErr.RootCauses())
+    assert.True(t, errors.Is(dependencyErr, errOther))
+    assert.True(t, errors.Is(dependencyErr, errPermanent))
+}
@@ -0,0 +1,206 @@
+package channeler
+
+import (
+    "errors"
+    "fmt"
+    "sort"
+    "strings"
+)
+
+/**
+Returned when a CallbackChain entry is nil or has no CallbackFunction to run
+ */
+type MissingCallbackError struct {
+    CallbackName string
+}
+func(err *MissingCallbackError) Error() string {
+    return fmt.Sprintf("%s has no callback function to run", err.CallbackName)
+}
+
+/**
+Returned when a ChanneledCallback lists a name in its DependenciesNames that is not a key of the CallbackChain
+ */
+type UnknownDependencyError struct {
+    CallbackName string
+    DependencyName string
+}
+func(err *UnknownDependencyError) Error() string {
+    return fmt.Sprintf("%s depends on %s which is not part of the callback chain", err.CallbackName, err.DependencyName)
+}
+
+/**
+Returned when a ChanneledCallback lists its own name in its DependenciesNames
+ */
+type SelfDependencyError struct {
+    CallbackName string
+}
+func(err *SelfDependencyError) Error() string {
+    return fmt.Sprintf("%s depends on itself", err.CallbackName)
+}
+
+/**
+Returned when a ChanneledCallback lists the same name several times in its DependenciesNames
+ */
+type DuplicateDependencyError struct {
+    CallbackName string
+    DependencyName string
+}
+func(err *DuplicateDependencyError) Error() string {
+    return fmt.Sprintf("%s lists %s more than once in its dependencies", err.CallbackName, err.DependencyName)
+}
+
+/**
+Returned when a ChanneledCallback has DependenciesOptions for a name missing from its DependenciesNames,
+e.g. a misspelled name given to WithOptionalDependencies() which would leave the real dependency required
+ */
+type UnlistedDependencyOptionsError struct {
+    CallbackName string
+    DependencyName string
+}
+func(err *UnlistedDependencyOptionsError) Error() string {
+    return fmt.Sprintf("%s has options for %s which is not one of its dependencies", err.CallbackName, err.DependencyName)
+}
+
+/**
+Returned when callbacks depend on each other in a loop, which would block Run() forever.
+Path starts and ends with the same callback name, e.g. [a b a] when a needs b and b needs a
+ */
+type CycleError struct {
+    Path []string
+}
+func(err *CycleError) Error() string {
+    return fmt.Sprintf("dependency cycle detected : %s", strings.Join(err.Path, " -> "))
+}
+
+/**
+Check the CallbackChain topology before running it. Every problem found is reported, joined with errors.Join
+so that callers can look for a given kind of problem with errors.As. Returns nil for a valid graph
+ */
+func (channeler *Channeler) Validate() error {
+    if (channeler.CallbackChain == nil) {
+        return nil
+    }
+    return (*channeler.CallbackChain).validate()
+}
+
+func (callbackChain CallbackChain) validate() error {
+    var problems []error
+    callbackNames := callbackChain.sortedNames()
+    for _, callbackName := range callbackNames {
+        channeledCallback := callbackChain[callbackName]
+        if (channeledCallback == nil || (channeledCallback.CallbackFunction == nil && channeledCallback.ContextCallbackFunction == nil)) {
+            problems = append(problems, &MissingCallbackError{callbackName})
+            if (channeledCallback == nil) {
+                continue
+            }
+        }
+        seen := map[string]bool{}
+        for _, dependencyName := range channeledCallback.DependenciesNames {
+            if (seen[dependencyName]) {
+                problems = append(problems, &DuplicateDependencyError{callbackName, dependencyName})
+                continue
+            }
+            seen[dependencyName] = true
+            if (dependencyName == callbackName) {
+                problems = append(problems, &SelfDependencyError{callbackName})
+            } else if _, exists := callbackChain[dependencyName]; !exists {
+                problems = append(problems, &UnknownDependencyError{callbackName, dependencyName})
+            }
+        }
+        var optionsNames []string
+        for dependencyName := range channeledCallback.DependenciesOptions {
+            if (!seen[dependencyName]) {
+                optionsNames = append(optionsNames, dependencyName)
+            }
+        }
+        sort.Strings(optionsNames)
+        for _, dependencyName := range optionsNames {
+            problems = append(problems, &UnlistedDependencyOptionsError{callbackName, dependencyName})
+        }
+    }
+    for _, cycle := range callbackChain.findCycles(callbackNames) {
+        problems = append(problems, &CycleError{cycle})
+    }
+    return errors.Join(problems...)
+}
+
+/**
+Depth-first walk of the dependencies, returning the path of every cycle closed by a back edge.
+Self-dependencies and unknown dependencies are reported on their own and ignored here
+ */
+func (callbackChain CallbackChain) findCycles(callbackNames []string) [][]string {
+    const (
+        unvisited = iota
+        visiting
+        visited
+    )
+    var cycles [][]string
+    states := map[string]int{}
+    var stack []string
+    var visit func(callbackName string)
+    visit = func(callbackName string) {
+        states[callbackName] = visiting
+        stack = append(stack, callbackName)
+        for _, dependencyName := range callbackChain.sortedDependencies(callbackName) {
+            switch states[dependencyName] {
+            case unvisited:
+                visit(dependencyName)
+            case visiting:
+                //walk the stack back to the dependency in order to extract the loop
+                for position := len(stack) - 1; position >= 0; position-- {
+                    if (stack[position] == dependencyName) {
+                        cycle := append([]string{}, stack[position:]...)
+                        cycles = append(cycles, append(cycle, dependencyName))
+                        break
+                    }
+                }
+            }
+        }
+        stack = stack[:len(stack)-1]
+        states[callbackName] = visited
+    }
+    for _, callbackName := range callbackNames {
+        if (states[callbackName] == unvisited) {
+            visit(callbackName)
+        }
+    }
+    return cycles
+}
+
+/**
+Return the names of the CallbackChain in a stable order
+ */
+func (callbackChain CallbackChain) sortedNames() []string {
+    callbackNames := make([]string, 0, len(callbackChain))
+    for callbackName := range callbackChain {
+        callbackNames = append(callbackNames, callbackName)
+    }
+    sort.Strings(callbackNames)
+    return callbackNames
+}
+
+/**
+Return the deduplicated, sorted dependencies of a callback which are existing keys of the CallbackChain, self-dependency excluded
+ */
+func (callbackChain CallbackChain) sortedDependencies(callbackName string) []string {
+    channeledCallback := callbackChain[callbackName]
+    if (channeledCallback == nil) {
+        return nil
+    }
+    var dependenciesNames []string
+    for _, dependencyName := range channeledCallback.DependenciesNames {
+        if _, exists := callbackChain[dependencyName]; !exists || dependencyName == callbackName {
+            continue
+        }
+        dependenciesNames = append(dependenciesNames, dependencyName)
+    }
+    sort.Strings(dependenciesNames)
+    //drop duplicates which are adjacent once sorted
+    deduplicated := dependenciesNames[:0]
+    for position, dependencyName := range dependenciesNames {
+        if (position == 0 || dependencyName != dependenciesNames[position-1]) {
+            deduplicated = append(deduplicated, dependencyName)
+        }
+    }
+    return deduplicated
+}
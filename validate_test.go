@@ -0,0 +1,75 @@
+package channeler
+
+import (
+    "errors"
+    "testing"
+    "github.com/stretchr/testify/assert"
+)
+
+func noopCallback(dependencies CallbackResults) (interface{}, error) {
+    return nil, nil
+}
+
+/**
+The standard fruits chain is a valid graph
+ */
+func TestChanneler_ValidateStandardChain(t *testing.T) {
+    channelerInstance := initFruitsChannelerWithStandardCbChain(t, timeDurationByFruitAndColor{})
+    assert.Nil(t, channelerInstance.Validate())
+}
+
+/**
+A cycle must be reported with its path and Run() must return instead of blocking forever
+ */
+func TestChanneler_ValidateCycle(t *testing.T) {
+    channelerInstance := NewChanneler(&CallbackChain{
+        "a": NewChanneledCallback(noopCallback, []string{"b"}),
+        "b": NewChanneledCallback(noopCallback, []string{"c"}),
+        "c": NewChanneledCallback(noopCallback, []string{"a"}),
+        "d": NewChanneledCallback(noopCallback, []string{"a"}),
+    })
+    var cycleErr *CycleError
+    assert.True(t, errors.As(channelerInstance.Validate(), &cycleErr))
+    assert.Equal(t, []string{"a", "b", "c", "a"}, cycleErr.Path)
+
+    _, err := channelerInstance.Run()
+    assert.True(t, errors.As(err, &cycleErr))
+    assert.Empty(t, channelerInstance.Results)
+}
+
+/**
+Unknown names, self-loops, duplicates and missing functions are all reported at once
+ */
+func TestChanneler_ValidateReportsEveryProblem(t *testing.T) {
+    channelerInstance := NewChanneler(&CallbackChain{
+        "a": NewChanneledCallback(noopCallback, []string{"a"}),
+        "b": NewChanneledCallback(noopCallback, []string{"missing"}),
+        "c": NewChanneledCallback(noopCallback, []string{"a", "a"}),
+        "d": NewChanneledCallback(nil, []string{}),
+        "e": NewChanneledCallback(noopCallback, []string{"b"}).WithOptionalDependencies("bb"),
+    })
+    err := channelerInstance.Validate()
+
+    var selfErr *SelfDependencyError
+    assert.True(t, errors.As(err, &selfErr))
+    assert.Equal(t, "a", selfErr.CallbackName)
+
+    var unknownErr *UnknownDependencyError
+    assert.True(t, errors.As(err, &unknownErr))
+    assert.Equal(t, &UnknownDependencyError{"b", "missing"}, unknownErr)
+
+    var duplicateErr *DuplicateDependencyError
+    assert.True(t, errors.As(err, &duplicateErr))
+    assert.Equal(t, &DuplicateDependencyError{"c", "a"}, duplicateErr)
+
+    var missingErr *MissingCallbackError
+    assert.True(t, errors.As(err, &missingErr))
+    assert.Equal(t, "d", missingErr.CallbackName)
+
+    var unlistedErr *UnlistedDependencyOptionsError
+    assert.True(t, errors.As(err, &unlistedErr))
+    assert.Equal(t, &UnlistedDependencyOptionsError{"e", "bb"}, unlistedErr)
+
+    var cycleErr *CycleError
+    assert.False(t, errors.As(err, &cycleErr))
+}